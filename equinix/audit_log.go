@@ -0,0 +1,155 @@
+package equinix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	auditLogPathEnvVar = "EQUINIX_AUDIT_LOG_PATH"
+
+	auditOutcomeSucceeded = "succeeded"
+	auditOutcomeFailed    = "failed"
+)
+
+var (
+	apiVersionSegmentRe = regexp.MustCompile(`^v\d+$`)
+	resourceIDSegmentRe = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|\d+)$`)
+)
+
+// auditResponseIDKeys are keys of response attributes holding identifiers
+// of created resources
+var auditResponseIDKeys = []string{"id", "uuid", "primaryConnectionId"}
+
+// auditLogEntry is a single mutating API operation written to audit log
+// as JSON line. Terraform does not pass resource addresses to providers,
+// thus operations are identified with API resource type and ID.
+type auditLogEntry struct {
+	Time         time.Time `json:"time"`
+	Service      string    `json:"service"`
+	Method       string    `json:"method"`
+	URL          string    `json:"url"`
+	ResourceType string    `json:"resource_type"`
+	TargetID     string    `json:"target_id,omitempty"`
+	Outcome      string    `json:"outcome"`
+	Status       int       `json:"status,omitempty"`
+	RequestID    string    `json:"request_id,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	RequestBody  string    `json:"request_body,omitempty"`
+}
+
+// auditLogTransport is a http.RoundTripper that appends every POST, PUT,
+// PATCH and DELETE request, including retries, to an audit log. Sensitive
+// request body values are redacted.
+type auditLogTransport struct {
+	service string
+	writer  *jsonLinesWriter
+	next    http.RoundTripper
+}
+
+// newAuditLogTransport wraps given transport with audit logging or returns
+// the transport as it is when audit log path is not set
+func newAuditLogTransport(service, path string, next http.RoundTripper) (http.RoundTripper, error) {
+	if path == "" {
+		return next, nil
+	}
+	writer, err := openJSONLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %s", err)
+	}
+	return &auditLogTransport{
+		service: service,
+		writer:  writer,
+		next:    next,
+	}, nil
+}
+
+func (t *auditLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadOnlyMethod(req.Method) {
+		return t.next.RoundTrip(req)
+	}
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resourceType, targetID := auditResource(req.URL.Path)
+	entry := &auditLogEntry{
+		Time:         time.Now().UTC(),
+		Service:      t.service,
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		ResourceType: resourceType,
+		TargetID:     targetID,
+		Outcome:      auditOutcomeFailed,
+		RequestBody:  traceBody(reqBody),
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		entry.RequestID = resp.Header.Get(requestIDHeader)
+		if resp.StatusCode < http.StatusBadRequest {
+			entry.Outcome = auditOutcomeSucceeded
+		}
+		if entry.TargetID == "" && resp.Body != nil {
+			respBody, rerr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+			if rerr == nil {
+				entry.TargetID = auditResponseID(respBody)
+			}
+		}
+	}
+	if werr := t.writer.write(entry); werr != nil {
+		log.Printf("[WARN] Failed to write audit log entry of %s %s: %s", req.Method, req.URL, werr)
+	}
+	return resp, err
+}
+
+// auditResource returns API resource type and target resource ID out of
+// request path, i.e. "projects/devices" and empty ID for
+// /metal/v1/projects/{id}/devices
+func auditResource(path string) (string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if apiVersionSegmentRe.MatchString(segment) {
+			segments = segments[i+1:]
+			break
+		}
+	}
+	var types []string
+	targetID := ""
+	for i, segment := range segments {
+		if resourceIDSegmentRe.MatchString(segment) {
+			if i == len(segments)-1 {
+				targetID = segment
+			}
+			continue
+		}
+		types = append(types, segment)
+	}
+	return strings.Join(types, "/"), targetID
+}
+
+// auditResponseID returns identifier of created resource out of JSON
+// response body
+func auditResponseID(body []byte) string {
+	var v map[string]interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return ""
+	}
+	for _, key := range auditResponseIDKeys {
+		if id, ok := v[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
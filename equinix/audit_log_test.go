@@ -0,0 +1,63 @@
+package equinix
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "req-123")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"6c3bd4ad-6c1e-4d54-9cf9-2e5d1b1b1d77","root_password":"secret"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	transport, err := newAuditLogTransport(serviceMetal, path, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("cannot create audit log transport: %s", err)
+	}
+	client := &http.Client{Transport: transport}
+	projectURL := server.URL + "/metal/v1/projects/8f3a4f8e-1ad4-4b8d-8e5c-1f7a39a1b7c2"
+	create, _ := http.NewRequest(http.MethodPost, projectURL+"/devices", strings.NewReader(`{"hostname":"test","password":"secret"}`))
+	get, _ := http.NewRequest(http.MethodGet, projectURL, nil)
+	remove, _ := http.NewRequest(http.MethodDelete, server.URL+"/metal/v1/devices/6c3bd4ad-6c1e-4d54-9cf9-2e5d1b1b1d77", nil)
+	// when
+	for _, req := range []*http.Request{create, get, remove} {
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+	}
+	// then
+	data, _ := ioutil.ReadFile(path)
+	assert.NotContains(t, string(data), "secret", "Secrets are redacted")
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	assert.Len(t, lines, 2, "Only mutating requests are logged")
+	entries := make([]auditLogEntry, len(lines))
+	for i := range lines {
+		assert.Nil(t, json.Unmarshal(lines[i], &entries[i]), "Entry is a JSON line")
+	}
+	assert.Equal(t, "projects/devices", entries[0].ResourceType)
+	assert.Equal(t, "6c3bd4ad-6c1e-4d54-9cf9-2e5d1b1b1d77", entries[0].TargetID, "Created resource ID is logged")
+	assert.Equal(t, auditOutcomeSucceeded, entries[0].Outcome)
+	assert.Equal(t, "req-123", entries[0].RequestID)
+	assert.Equal(t, "devices", entries[1].ResourceType)
+	assert.Equal(t, "6c3bd4ad-6c1e-4d54-9cf9-2e5d1b1b1d77", entries[1].TargetID)
+	assert.Equal(t, auditOutcomeFailed, entries[1].Outcome)
+	assert.Equal(t, http.StatusUnprocessableEntity, entries[1].Status)
+}
@@ -2,42 +2,27 @@ package equinix
 
 import (
 	"context"
-	"crypto/x509"
 	"fmt"
 	"log"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"os"
 	"path"
-	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/equinix/ecx-go/v2"
 	"github.com/equinix/ne-go"
 	"github.com/equinix/oauth2-go"
 	"github.com/equinix/terraform-provider-equinix/version"
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/meta"
 	"github.com/packethost/packngo"
 	xoauth2 "golang.org/x/oauth2"
 )
 
-type DumpTransport struct {
-	r http.RoundTripper
-}
-
-func (d *DumpTransport) RoundTrip(h *http.Request) (*http.Response, error) {
-	dump, _ := httputil.DumpRequestOut(h, true)
-	fmt.Printf("****REQUEST****\n%q\n", dump)
-	resp, err := d.r.RoundTrip(h)
-	dump, _ = httputil.DumpResponse(resp, true)
-	fmt.Printf("****RESPONSE****\n%q\n****************\n\n", dump)
-	return resp, err
-}
-
 const (
 	consumerToken         = "aZ9GmqHTPtxevvFq9SK3Pi2yr9YCbRzduCSXF2SNem5sjB91mDq7Th3ZwTtRqMWZ"
 	metalBasePath         = "/metal/v1/"
@@ -62,8 +47,9 @@ Original Error:`
 )
 
 var (
-	DefaultBaseURL = "https://api.equinix.com"
-	DefaultTimeout = 30
+	DefaultBaseURL      = "https://api.equinix.com"
+	DefaultTimeout      = 30
+	DefaultMaxRetryWait = 30
 )
 
 // Config is the configuration structure used to instantiate the Equinix
@@ -78,95 +64,327 @@ type Config struct {
 	RequestTimeout time.Duration
 	PageSize       int
 	Token          string
+	RateLimits     map[string]RateLimit
+	Endpoints      map[string]string
+	DefaultTags    []string
+	ProtectedTags  []string
+
+	CACertFile         string
+	ClientCertFile     string
+	ClientKeyFile      string
+	ProxyURL           string
+	InsecureSkipVerify bool
+	HTTPTraceFile      string
+	TokenCacheFile     string
+	ReadOnly           bool
+	AuditLogPath       string
+	CatalogCacheTTL    time.Duration
+	PollInterval       time.Duration
+	PollMaxInterval    time.Duration
+	HashSecrets        bool
 
 	ecx   ecx.Client
 	ne    ne.Client
 	metal *packngo.Client
 
+	clients *configClients
+	// operationCtx is the context of resource operation that Equinix Metal
+	// clients send requests with, see withContext
+	operationCtx context.Context
+
+	ctx       context.Context
+	transport http.RoundTripper
+	readCache *readCache
+
 	terraformVersion string
 }
 
-// Load function validates configuration structure fields and configures
-// all required API clients.
+// Load function validates configuration structure fields and shared
+// transport settings. API clients are created on first use of a service.
 func (c *Config) Load(ctx context.Context) error {
 	if c.BaseURL == "" {
 		return fmt.Errorf("'baseURL' cannot be empty")
 	}
 
-	if c.Token == "" && (c.ClientID == "" || c.ClientSecret == "") && c.AuthToken == "" {
+	replaying := recorderMode() == recorderModeReplay
+	if !replaying && c.Token == "" && (c.ClientID == "" || c.ClientSecret == "") && c.AuthToken == "" {
 		return fmt.Errorf(emptyCredentialsError)
 	}
 
-	var authClient *http.Client
-	if c.Token != "" {
-		tokenSource := xoauth2.StaticTokenSource(&xoauth2.Token{AccessToken: c.Token})
-		oauthTransport := &xoauth2.Transport{
-			Source: tokenSource,
-		}
-		authClient = &http.Client{
-			Transport: oauthTransport,
-		}
-	} else {
-		authConfig := oauth2.Config{
-			ClientID:     c.ClientID,
-			ClientSecret: c.ClientSecret,
-			BaseURL:      c.BaseURL,
-		}
-		authClient = authConfig.New(ctx)
+	// settings shared by all clients are validated up front, so lazily
+	// created clients do not fail on first use
+	transport, err := c.httpTransport()
+	if err != nil {
+		return err
+	}
+	if _, err := newRecorderTransport(transport); err != nil {
+		return err
+	}
+	if _, err := newHTTPTraceTransport("", c.HTTPTraceFile, transport); err != nil {
+		return err
+	}
+	if _, err := newAuditLogTransport("", c.AuditLogPath, transport); err != nil {
+		return err
+	}
+	c.ctx = ctx
+	c.readCache = newReadCache(c.CatalogCacheTTL)
+	c.clients = &configClients{}
+	return nil
+}
+
+// configClients are API clients of a configuration, created on first use
+// and shared by its copies bound to operation context
+type configClients struct {
+	ecxHTTPClient   *http.Client
+	neHTTPClient    *http.Client
+	metalHTTPClient *http.Client
+	metal           *packngo.Client
+	devicePoller    *devicePoller
+
+	ecxOnce          sync.Once
+	neOnce           sync.Once
+	metalOnce        sync.Once
+	devicePollerOnce sync.Once
+}
+
+// sharedClients returns clients of the configuration. They are created by
+// Load, configurations that were not loaded get them on first use.
+func (c *Config) sharedClients() *configClients {
+	if c.clients == nil {
+		c.clients = &configClients{}
+	}
+	return c.clients
+}
+
+// withContext returns copy of the configuration for a single resource
+// operation. Equinix Metal clients of the copy send requests with given
+// context, so they are canceled and traced with the operation. API clients
+// and caches are shared with the original configuration.
+func (c *Config) withContext(ctx context.Context) *Config {
+	c.sharedClients()
+	op := *c
+	op.operationCtx = ctx
+	return &op
+}
+
+// metalClient returns Equinix Metal client sending requests with the
+// context of operation the configuration is bound to, if any
+func (c *Config) metalClient() *packngo.Client {
+	shared := c.sharedMetalClient()
+	if c.metal != nil || c.operationCtx == nil {
+		return shared
+	}
+	httpClient := *c.clients.metalHTTPClient
+	httpClient.Transport = &contextTransport{ctx: c.operationCtx, next: httpClient.Transport}
+	return c.newMetalClient(&httpClient)
+}
+
+// sharedMetalClient returns Equinix Metal client shared by all operations,
+// created on first use
+func (c *Config) sharedMetalClient() *packngo.Client {
+	if c.metal != nil {
+		return c.metal
 	}
-	authClient.Timeout = c.requestTimeout()
-	authClient.Transport = logging.NewTransport("Equinix", authClient.Transport)
-	ecxClient := ecx.NewClient(ctx, c.BaseURL, authClient)
-	neClient := ne.NewClient(ctx, c.BaseURL, authClient)
+	clients := c.sharedClients()
+	clients.metalOnce.Do(func() {
+		clients.metalHTTPClient = c.newMetalHTTPClient()
+		clients.metal = c.newMetalClient(clients.metalHTTPClient)
+	})
+	return clients.metal
+}
+
+// ecxClient returns Equinix Fabric client sending requests with a given
+// context, i.e. the context of resource operation. Clients share HTTP client
+// with authentication, created on first use.
+func (c *Config) ecxClient(ctx context.Context) ecx.Client {
+	if c.ecx != nil {
+		return c.ecx
+	}
+	clients := c.sharedClients()
+	clients.ecxOnce.Do(func() {
+		clients.ecxHTTPClient = c.newAuthenticatedHTTPClient(serviceFabric)
+	})
+	client := ecx.NewClient(ctx, c.endpoint(serviceFabric), clients.ecxHTTPClient)
 	if c.PageSize > 0 {
-		ecxClient.SetPageSize(c.PageSize)
-		neClient.SetPageSize(c.PageSize)
+		client.SetPageSize(c.PageSize)
 	}
-	ecxClient.SetHeaders(map[string]string{
+	client.SetHeaders(map[string]string{
 		"User-agent": c.fullUserAgent("equinix/ecx-go"),
 	})
-	neClient.SetHeaders(map[string]string{
+	return client
+}
+
+// neClient returns Equinix Network Edge client sending requests with a given
+// context. Clients share HTTP client with authentication, created on first
+// use.
+func (c *Config) neClient(ctx context.Context) ne.Client {
+	if c.ne != nil {
+		return c.ne
+	}
+	clients := c.sharedClients()
+	clients.neOnce.Do(func() {
+		clients.neHTTPClient = c.newAuthenticatedHTTPClient(serviceNetworkEdge)
+	})
+	client := ne.NewClient(ctx, c.endpoint(serviceNetworkEdge), clients.neHTTPClient)
+	if c.PageSize > 0 {
+		client.SetPageSize(c.PageSize)
+	}
+	client.SetHeaders(map[string]string{
 		"User-agent": c.fullUserAgent("equinix/ne-go"),
 	})
+	return client
+}
 
-	c.ecx = ecxClient
-	c.ne = neClient
-	c.metal = c.NewMetalClient()
-
-	return nil
+// metalDevicePoller returns poller of Equinix Metal device status shared by
+// all device resources, created on first use
+func (c *Config) metalDevicePoller() *devicePoller {
+	clients := c.sharedClients()
+	clients.devicePollerOnce.Do(func() {
+		clients.devicePoller = newDevicePoller(c.sharedMetalClient(), devicePollInterval)
+	})
+	return clients.devicePoller
 }
 
-func (c *Config) requestTimeout() time.Duration {
-	if c.RequestTimeout == 0 {
-		return 5 * time.Second
+func (c *Config) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
 	}
-	return c.RequestTimeout
+	return c.ctx
 }
 
-var redirectsErrorRe = regexp.MustCompile(`stopped after \d+ redirects\z`)
-
-func MetalRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
-	if ctx.Err() != nil {
-		return false, ctx.Err()
+// ValidateCredentials calls cheap endpoint of every service that has
+// credentials configured, so invalid credentials are reported when provider
+// is configured rather than on first resource operation. Fabric and Network
+// Edge share credentials, but not every account has access to Network Edge,
+// thus its failures are reported as warnings.
+func (c *Config) ValidateCredentials(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+	validate := func(service string, severity diag.Severity, check func(context.Context) error) {
+		ctx := withRequestIDRecorder(ctx)
+		if err := check(ctx); err != nil {
+			for _, d := range diagFromAPIError(ctx, err, nil) {
+				d.Severity = severity
+				d.Summary = fmt.Sprintf("%s credentials validation failed: %s", serviceTitles[service], d.Summary)
+				diags = append(diags, d)
+			}
+		}
 	}
+	if c.AuthToken != "" {
+		validate(serviceMetal, diag.Error, func(context.Context) error {
+			// project API keys can't read the current user, but list
+			// projects they have access to, like user API keys do
+			_, _, err := c.metalClient().Projects.List(&packngo.ListOptions{Page: 1, PerPage: 1})
+			return err
+		})
+	}
+	if c.Token != "" || (c.ClientID != "" && c.ClientSecret != "") {
+		validate(serviceFabric, diag.Error, func(ctx context.Context) error {
+			_, err := c.ecxClient(ctx).GetUserPorts()
+			return err
+		})
+		validate(serviceNetworkEdge, diag.Warning, func(ctx context.Context) error {
+			_, err := c.neClient(ctx).GetSSHPublicKeys()
+			return err
+		})
+	}
+	return diags
+}
 
-	if err != nil {
-		if v, ok := err.(*url.Error); ok {
-			// Don't retry if the error was due to too many redirects.
-			if redirectsErrorRe.MatchString(v.Error()) {
-				return false, nil
-			}
+// endpoint returns API base URL of a given service, falling back to
+// common base URL when service endpoint is not overridden. Equinix Metal
+// client resolves request paths relative to its base URL, so Equinix Metal
+// endpoint always ends with a slash.
+func (c *Config) endpoint(service string) string {
+	if endpoint := c.Endpoints[service]; endpoint != "" {
+		if service == serviceMetal && !strings.HasSuffix(endpoint, "/") {
+			return endpoint + "/"
+		}
+		return endpoint
+	}
+	if service == serviceMetal {
+		baseURL, _ := url.Parse(c.BaseURL)
+		baseURL.Path = path.Join(baseURL.Path, metalBasePath) + "/"
+		return baseURL.String()
+	}
+	return c.BaseURL
+}
 
-			// Don't retry if the error was due to TLS cert verification failure.
-			if _, ok := v.Err.(x509.UnknownAuthorityError); ok {
-				return false, nil
-			}
+// newAuthenticatedHTTPClient returns HTTP client of a given OAuth
+// authenticated service. Tokens are acquired from the service endpoint
+// with the same base transport as API requests.
+func (c *Config) newAuthenticatedHTTPClient(service string) *http.Client {
+	transport := c.baseTransport()
+	replaying := recorderMode() == recorderModeReplay
+	var tokenSource xoauth2.TokenSource
+	if c.Token != "" || replaying {
+		// replayed interactions are not authorized, thus there is no need
+		// to acquire real token
+		token := c.Token
+		if token == "" {
+			token = redactedValue
 		}
+		tokenSource = xoauth2.StaticTokenSource(&xoauth2.Token{AccessToken: token})
+	} else {
+		tokenSource = c.newClientCredentialsTokenSource(service, transport)
+	}
+	if recorder, err := newRecorderTransport(transport); err != nil {
+		log.Printf("[WARN] HTTP recorder disabled for %s client: %s", serviceTitles[service], err)
+	} else {
+		transport = recorder
+	}
+	transport = &xoauth2.Transport{
+		Source: tokenSource,
+		Base:   transport,
+	}
+	if trace, err := newHTTPTraceTransport(service, c.HTTPTraceFile, transport); err != nil {
+		log.Printf("[WARN] HTTP trace disabled for %s client: %s", serviceTitles[service], err)
+	} else {
+		transport = trace
+	}
+	if audit, err := newAuditLogTransport(service, c.AuditLogPath, transport); err != nil {
+		log.Printf("[WARN] Audit log disabled for %s client: %s", serviceTitles[service], err)
+	} else {
+		transport = audit
+	}
+	transport = newRequestIDTransport(transport)
+	transport = logging.NewTransport("Equinix", newThrottlingTransport(service, c.RateLimits[service], transport))
+	transport = newTelemetryTransport(service, transport)
+	transport = newReadOnlyTransport(c.ReadOnly, transport)
+	client := c.newRetryableHTTPClient(service, transport, c.requestTimeout())
+	client.Transport = newReadCacheTransport(c.readCache, service, client.Transport)
+	return client
+}
+
+// newClientCredentialsTokenSource returns source of OAuth tokens acquired
+// with client ID and secret from a given service endpoint, cached in token
+// cache file when it is configured
+func (c *Config) newClientCredentialsTokenSource(service string, transport http.RoundTripper) xoauth2.TokenSource {
+	authConfig := oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		BaseURL:      c.endpoint(service),
+	}
+	httpClient := &http.Client{
+		Transport: transport,
+		Timeout:   c.requestTimeout(),
+	}
+	if c.TokenCacheFile == "" {
+		return authConfig.TokenSource(c.context(), httpClient)
+	}
+	// token source of oauth2 package reuses its token until expiry, so
+	// a new one is used for every fetch to refresh tokens before cached
+	// ones expire
+	fetch := tokenSourceFunc(func() (*xoauth2.Token, error) {
+		return authConfig.TokenSource(c.context(), httpClient).Token()
+	})
+	return newCachedTokenSource(c.TokenCacheFile, c.ClientID, authConfig.BaseURL, fetch)
+}
 
-		// The error is likely recoverable so retry.
-		return true, nil
+func (c *Config) requestTimeout() time.Duration {
+	if c.RequestTimeout == 0 {
+		return 5 * time.Second
 	}
-	return false, nil
+	return c.RequestTimeout
 }
 
 func terraformUserAgent(version string) string {
@@ -192,20 +410,38 @@ func (c *Config) fullUserAgent(suffix string) string {
 
 // NewMetalClient returns a new client for accessing Equinix Metal's API.
 func (c *Config) NewMetalClient() *packngo.Client {
-	transport := http.DefaultTransport
-	// transport = &DumpTransport{http.DefaultTransport} // Debug only
+	return c.newMetalClient(c.newMetalHTTPClient())
+}
+
+// newMetalHTTPClient returns HTTP client of Equinix Metal clients
+func (c *Config) newMetalHTTPClient() *http.Client {
+	transport := c.baseTransport()
+	if recorder, err := newRecorderTransport(transport); err != nil {
+		log.Printf("[WARN] HTTP recorder disabled for Equinix Metal client: %s", err)
+	} else {
+		transport = recorder
+	}
+	if trace, err := newHTTPTraceTransport(serviceMetal, c.HTTPTraceFile, transport); err != nil {
+		log.Printf("[WARN] HTTP trace disabled for Equinix Metal client: %s", err)
+	} else {
+		transport = trace
+	}
+	if audit, err := newAuditLogTransport(serviceMetal, c.AuditLogPath, transport); err != nil {
+		log.Printf("[WARN] Audit log disabled for Equinix Metal client: %s", err)
+	} else {
+		transport = audit
+	}
+	transport = newThrottlingTransport(serviceMetal, c.RateLimits[serviceMetal], transport)
 	transport = logging.NewTransport("Equinix Metal", transport)
-	retryClient := retryablehttp.NewClient()
-	retryClient.HTTPClient.Transport = transport
-	retryClient.RetryMax = c.MaxRetries
-	retryClient.RetryWaitMin = time.Second
-	retryClient.RetryWaitMax = c.MaxRetryWait
-	retryClient.CheckRetry = MetalRetryPolicy
-	standardClient := retryClient.StandardClient()
-	baseURL, _ := url.Parse(c.BaseURL)
-	baseURL.Path = path.Join(baseURL.Path, metalBasePath) + "/"
-	client, _ := packngo.NewClientWithBaseURL(consumerToken, c.AuthToken, standardClient, baseURL.String())
-	client.UserAgent = c.fullUserAgent(client.UserAgent)
+	transport = newTelemetryTransport(serviceMetal, transport)
+	transport = newReadOnlyTransport(c.ReadOnly, transport)
+	standardClient := c.newRetryableHTTPClient(serviceMetal, transport, 0)
+	standardClient.Transport = newReadCacheTransport(c.readCache, serviceMetal, standardClient.Transport)
+	return standardClient
+}
 
+func (c *Config) newMetalClient(httpClient *http.Client) *packngo.Client {
+	client, _ := packngo.NewClientWithBaseURL(consumerToken, c.AuthToken, httpClient, c.endpoint(serviceMetal))
+	client.UserAgent = c.fullUserAgent(client.UserAgent)
 	return client
 }
@@ -0,0 +1,143 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_endpoint(t *testing.T) {
+	// given
+	config := &Config{
+		BaseURL: "https://api.equinix.com",
+		Endpoints: map[string]string{
+			serviceFabric: "https://uatapi.equinix.com",
+		},
+	}
+	// when
+	metal := config.endpoint(serviceMetal)
+	fabric := config.endpoint(serviceFabric)
+	ne := config.endpoint(serviceNetworkEdge)
+	// then
+	assert.Equal(t, "https://api.equinix.com/metal/v1/", metal, "Metal endpoint is derived from base URL")
+	assert.Equal(t, "https://uatapi.equinix.com", fabric, "Fabric endpoint is overridden")
+	assert.Equal(t, "https://api.equinix.com", ne, "Network Edge endpoint falls back to base URL")
+}
+
+func TestConfig_endpointMetalOverride(t *testing.T) {
+	// given
+	config := &Config{
+		BaseURL: "https://api.equinix.com",
+		Endpoints: map[string]string{
+			serviceMetal: "https://metal.example.com/metal/v1",
+		},
+	}
+	// when
+	metal := config.endpoint(serviceMetal)
+	// then
+	assert.Equal(t, "https://metal.example.com/metal/v1/", metal, "Metal endpoint override ends with slash")
+}
+
+func TestConfig_lazyClients(t *testing.T) {
+	// given
+	config := &Config{
+		BaseURL:   "https://api.equinix.com",
+		AuthToken: "metalToken",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Nil(t, config.clients.metal, "Metal client is not created on load")
+	assert.Nil(t, config.clients.ecxHTTPClient, "Fabric client is not created on load")
+	assert.NotNil(t, config.metalClient(), "Metal client is created on first use")
+	assert.Same(t, config.metalClient(), config.metalClient(), "Metal client is created once")
+	assert.Nil(t, config.clients.ecxHTTPClient, "Fabric client is not created with Metal client")
+}
+
+func TestConfig_withContext(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"projects":[],"meta":{"total":0,"current_page":1,"last_page":1}}`))
+	}))
+	defer server.Close()
+	config := testFakeAPIConfig(t, server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// when
+	operation := config.withContext(ctx)
+	_, _, err := config.metalClient().Projects.List(nil)
+	_, _, operationErr := operation.metalClient().Projects.List(nil)
+	// then
+	assert.Nil(t, err, "Request of shared client is sent")
+	assert.NotNil(t, operationErr, "Request of operation client is canceled with its context")
+	assert.Same(t, config.sharedMetalClient(), operation.sharedMetalClient(), "Operation shares clients of configuration")
+	assert.Nil(t, config.operationCtx, "Configuration is not bound to operation context")
+}
+
+func TestConfig_validateCredentials(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(requestIDHeader, "req-123")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errors":["Invalid authentication token"]}`))
+	}))
+	defer server.Close()
+	config := &Config{
+		BaseURL:   server.URL,
+		AuthToken: "invalidToken",
+	}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	// when
+	diags := config.ValidateCredentials(context.Background())
+	// then
+	assert.Len(t, diags, 1, "Only Metal credentials are validated")
+	assert.True(t, strings.HasPrefix(diags[0].Summary, "Equinix Metal credentials validation failed"), "Diagnostic is service specific")
+	assert.Contains(t, diags[0].Detail, "Request ID: req-123")
+}
+
+func TestConfig_validateCredentialsNetworkEdge(t *testing.T) {
+	// given
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/ne/"):
+			w.Header().Set(requestIDHeader, "req-ne")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`[{"errorCode":"IC-NE-403","errorMessage":"Access denied"}]`))
+		case strings.HasPrefix(r.URL.Path, "/metal/"):
+			_, _ = w.Write([]byte(`{"projects":[],"meta":{"total":0,"current_page":1,"last_page":1}}`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+	config := &Config{
+		BaseURL:   server.URL,
+		AuthToken: "projectToken",
+		Token:     "fabricToken",
+	}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	// when
+	diags := config.ValidateCredentials(context.Background())
+	// then
+	assert.False(t, diags.HasError(), "Network Edge failure is not an error")
+	assert.Len(t, diags, 1, "Network Edge failure is reported")
+	assert.Equal(t, diag.Warning, diags[0].Severity, "Network Edge failure is a warning")
+	assert.True(t, strings.HasPrefix(diags[0].Summary, "Equinix Network Edge credentials validation failed"), "Diagnostic is service specific")
+	assert.Contains(t, diags[0].Detail, "Request ID: req-ne", "Request ID of Network Edge error is reported")
+	assert.Contains(t, paths, "/metal/v1/projects", "Metal credentials are validated with endpoint allowed for project API keys")
+}
@@ -234,7 +234,7 @@ func dataSourceECXL2SellerProfileRead(ctx context.Context, d *schema.ResourceDat
 	name := d.Get(ecxL2SellerProfileSchemaNames["Name"]).(string)
 	orgName := d.Get(ecxL2SellerProfileSchemaNames["OrganizationName"]).(string)
 	orgGlobalName := d.Get(ecxL2SellerProfileSchemaNames["GlobalOrganization"]).(string)
-	profiles, err := conf.ecx.GetL2SellerProfiles()
+	profiles, err := conf.ecxClient(ctx).GetL2SellerProfiles()
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -89,7 +89,7 @@ func dataSourceECXL2SellerProfiles() *schema.Resource {
 func dataSourceECXL2SellerProfilesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	profiles, err := conf.ecx.GetL2SellerProfiles()
+	profiles, err := conf.ecxClient(ctx).GetL2SellerProfiles()
 	if err != nil {
 		return diag.FromErr(err)
 	}
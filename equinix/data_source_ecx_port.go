@@ -100,7 +100,7 @@ func dataSourceECXPortRead(ctx context.Context, d *schema.ResourceData, m interf
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	name := d.Get(ecxPortSchemaNames["Name"]).(string)
-	ports, err := conf.ecx.GetUserPorts()
+	ports, err := conf.ecxClient(ctx).GetUserPorts()
 	if err != nil {
 		return diag.FromErr(err)
 	}
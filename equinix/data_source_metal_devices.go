@@ -0,0 +1,306 @@
+package equinix
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+)
+
+// Device attributes matched by the search parameter of device listing. Values
+// are searched approximately, so filters are still applied to listed devices.
+// Other attributes are not searched by the API, so their filters are applied
+// only to listed devices.
+var deviceSearchAttributes = []string{"hostname"}
+
+// Maximum page size of device listing accepted by the API
+const maxDevicesPerPage = 1000
+
+func dataSourceMetalDevices() *schema.Resource {
+	dataListConfig := &datalist.ResourceConfig{
+		RecordSchema:               deviceListSchema(),
+		ResultAttributeName:        "devices",
+		ResultAttributeDescription: "Sorted list of devices that match the specified filters",
+		FlattenRecord:              flattenDevice,
+		GetRecords:                 getDevices,
+		PushdownQuery:              pushdownDeviceQuery,
+		ExtraQuerySchema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of project containing the devices",
+				Optional:     true,
+				ExactlyOneOf: []string{"project_id", "organization_id"},
+			},
+			"organization_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of organization containing the devices",
+				Optional:     true,
+				ExactlyOneOf: []string{"project_id", "organization_id"},
+			},
+		},
+	}
+
+	return datalist.NewResource(dataListConfig)
+}
+
+// Translates the first filter on a searchable attribute with a single value
+// into the search parameter of device listing. All filters are returned, as
+// search matches more devices than the filter. Limit is pushed down only for
+// queries without filters and sorts, which take any listed devices.
+func pushdownDeviceQuery(meta interface{}, extra map[string]interface{}, filters []datalist.Filter, sorts []datalist.Sort, limit int) (map[string]interface{}, []datalist.Filter, error) {
+	for _, f := range filters {
+		if f.MatchBy != "in" || len(f.Values) != 1 || !isStringInSlice(f.Attribute, deviceSearchAttributes) {
+			continue
+		}
+		if search, ok := f.Values[0].(string); ok && search != "" {
+			return map[string]interface{}{"search": search}, filters, nil
+		}
+	}
+	if len(filters) == 0 && len(sorts) == 0 && limit > 0 {
+		return map[string]interface{}{"limit": limit}, filters, nil
+	}
+	return nil, filters, nil
+}
+
+func getDevices(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+	client := meta.(*Config).metalClient()
+	opts := &packngo.ListOptions{
+		Includes: deviceCommonIncludes,
+	}
+	if search, ok := extra["search"].(string); ok {
+		opts.Search = search
+	}
+	limit, _ := extra["limit"].(int)
+
+	projectIDs := []string{}
+	if projectID, ok := extra["project_id"].(string); ok && projectID != "" {
+		projectIDs = append(projectIDs, projectID)
+	} else if organizationID, ok := extra["organization_id"].(string); ok && organizationID != "" {
+		projects, _, err := client.Projects.List(nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if path.Base(p.Organization.URL) == organizationID {
+				projectIDs = append(projectIDs, p.ID)
+			}
+		}
+	} else {
+		return nil, fmt.Errorf("You must supply project_id or organization_id")
+	}
+
+	devicesIf := []interface{}{}
+	for _, projectID := range projectIDs {
+		var devices []packngo.Device
+		var err error
+		if limit > 0 {
+			devices, err = listDevicesPages(client, projectID, opts, limit-len(devicesIf))
+		} else {
+			devices, _, err = client.Devices.List(projectID, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to list devices of project %s: %s", projectID, err)
+		}
+		for _, d := range devices {
+			devicesIf = append(devicesIf, d)
+		}
+		if limit > 0 && len(devicesIf) >= limit {
+			break
+		}
+	}
+	return devicesIf, nil
+}
+
+// listDevicesPages lists pages of project devices until at least limit
+// devices or the last page are listed. Devices.List requests all pages unless
+// a page is set and it does not return the page metadata, so a page smaller
+// than requested is taken as the last one.
+func listDevicesPages(client *packngo.Client, projectID string, opts *packngo.ListOptions, limit int) ([]packngo.Device, error) {
+	pageOpts := *opts
+	pageOpts.PerPage = limit
+	if pageOpts.PerPage > maxDevicesPerPage {
+		pageOpts.PerPage = maxDevicesPerPage
+	}
+	var devices []packngo.Device
+	for pageOpts.Page = 1; ; pageOpts.Page++ {
+		page, _, err := client.Devices.List(projectID, &pageOpts)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, page...)
+		if len(devices) >= limit || len(page) < pageOpts.PerPage {
+			return devices, nil
+		}
+	}
+}
+
+func deviceListSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Description: "ID of the device",
+		},
+		"hostname": {
+			Type:        schema.TypeString,
+			Description: "The device name",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Description: "Description string for the device",
+		},
+		"project_id": {
+			Type:        schema.TypeString,
+			Description: "The id of the project in which the devices exists",
+		},
+		"facility": {
+			Type:        schema.TypeString,
+			Description: "The facility where the device is deployed",
+		},
+		"metro": {
+			Type:        schema.TypeString,
+			Description: "The metro where the device is deployed",
+		},
+		"plan": {
+			Type:        schema.TypeString,
+			Description: "The hardware config of the device",
+		},
+		"operating_system": {
+			Type:        schema.TypeString,
+			Description: "The operating system running on the device",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Description: "The state of the device",
+		},
+		"billing_cycle": {
+			Type:        schema.TypeString,
+			Description: "The billing cycle of the device (monthly or hourly)",
+		},
+		"access_public_ipv6": {
+			Type:        schema.TypeString,
+			Description: "The ipv6 management IP assigned to the device",
+		},
+		"access_public_ipv4": {
+			Type:        schema.TypeString,
+			Description: "The ipv4 management IP assigned to the device",
+		},
+		"access_private_ipv4": {
+			Type:        schema.TypeString,
+			Description: "The ipv4 private IP assigned to the device",
+		},
+		"tags": {
+			Type:        schema.TypeList,
+			Description: "Tags attached to the device",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"network_type": {
+			Type:        schema.TypeString,
+			Description: "L2 network type of the device, one of" + NetworkTypeList,
+		},
+		"hardware_reservation_id": {
+			Type:        schema.TypeString,
+			Description: "The id of hardware reservation which this device occupies",
+		},
+		"always_pxe": {
+			Type:        schema.TypeBool,
+			Description: "Whether the device boots from iPXE on every boot",
+		},
+		"ipxe_script_url": {
+			Type:        schema.TypeString,
+			Description: "URL of the iPXE script of the device",
+		},
+		"network": {
+			Type:        schema.TypeList,
+			Description: "The device's private and public IP (v4 and v6) network details",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address": {
+						Type:        schema.TypeString,
+						Description: "IPv4 or IPv6 address string",
+					},
+					"gateway": {
+						Type:        schema.TypeString,
+						Description: "Address of router",
+					},
+					"family": {
+						Type:        schema.TypeInt,
+						Description: "IP version - \"4\" or \"6\"",
+					},
+					"cidr": {
+						Type:        schema.TypeInt,
+						Description: "Bit length of the network mask of the address",
+					},
+					"public": {
+						Type:        schema.TypeBool,
+						Description: "Whether the address is routable from the Internet",
+					},
+				},
+			},
+		},
+	}
+}
+
+func flattenDevice(rawDevice interface{}, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	device, ok := rawDevice.(packngo.Device)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert to packngo.Device")
+	}
+
+	networkInfo := getNetworkInfo(device.Network)
+
+	sort.SliceStable(networkInfo.Networks, func(i, j int) bool {
+		famI := networkInfo.Networks[i]["family"].(int)
+		famJ := networkInfo.Networks[j]["family"].(int)
+		pubI := networkInfo.Networks[i]["public"].(bool)
+		pubJ := networkInfo.Networks[j]["public"].(bool)
+		return getNetworkRank(famI, pubI) < getNetworkRank(famJ, pubJ)
+	})
+
+	networks := make([]interface{}, len(networkInfo.Networks))
+	for i, n := range networkInfo.Networks {
+		networks[i] = n
+	}
+
+	flattenedDevice := map[string]interface{}{
+		"id":                  device.ID,
+		"hostname":            device.Hostname,
+		"state":               device.State,
+		"billing_cycle":       device.BillingCycle,
+		"tags":                stringArrToIfArr(device.Tags),
+		"network_type":        device.GetNetworkType(),
+		"always_pxe":          device.AlwaysPXE,
+		"ipxe_script_url":     device.IPXEScriptURL,
+		"network":             networks,
+		"access_public_ipv4":  networkInfo.PublicIPv4,
+		"access_private_ipv4": networkInfo.PrivateIPv4,
+		"access_public_ipv6":  networkInfo.PublicIPv6,
+	}
+
+	if device.Description != nil {
+		flattenedDevice["description"] = *device.Description
+	}
+	if device.Project != nil {
+		flattenedDevice["project_id"] = device.Project.ID
+	}
+	if device.Facility != nil {
+		flattenedDevice["facility"] = device.Facility.Code
+	}
+	if device.Metro != nil {
+		flattenedDevice["metro"] = strings.ToLower(device.Metro.Code)
+	}
+	if device.Plan != nil {
+		flattenedDevice["plan"] = device.Plan.Slug
+	}
+	if device.OS != nil {
+		flattenedDevice["operating_system"] = device.OS.Slug
+	}
+	if device.HardwareReservation != nil {
+		flattenedDevice["hardware_reservation_id"] = device.HardwareReservation.ID
+	}
+
+	return flattenedDevice, nil
+}
@@ -0,0 +1,72 @@
+package equinix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceMetalDevices_basic(t *testing.T) {
+	projectName := fmt.Sprintf("ds-devices-%s", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccMetalDeviceCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceMetalDevicesConfig_basic(projectName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.equinix_metal_devices.test", "result_count", "1"),
+					resource.TestCheckResourceAttr(
+						"data.equinix_metal_devices.test", "devices.0.hostname", "tfacc-test-devices"),
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_device.test", "id",
+						"data.equinix_metal_devices.test", "ids.0"),
+					resource.TestCheckResourceAttrSet(
+						"data.equinix_metal_devices.test", "devices.0.access_public_ipv4"),
+				),
+			},
+		},
+	})
+}
+
+func testDataSourceMetalDevicesConfig_basic(projSuffix string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_project" "test" {
+    name = "tfacc-project-%s"
+}
+
+resource "equinix_metal_device" "test" {
+  hostname         = "tfacc-test-devices"
+  plan             = local.plan
+  metro            = local.metro
+  operating_system = local.os
+  billing_cycle    = "hourly"
+  project_id       = "${equinix_metal_project.test.id}"
+  termination_time = "%s"
+
+  lifecycle {
+    ignore_changes = [
+      plan,
+      metro,
+    ]
+  }
+}
+
+data "equinix_metal_devices" "test" {
+  project_id = equinix_metal_project.test.id
+
+  filter {
+    attribute = "hostname"
+    values    = [equinix_metal_device.test.hostname]
+  }
+
+  require_single_result = true
+}`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), projSuffix, testDeviceTerminationTime())
+}
@@ -0,0 +1,127 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/datalist"
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceMetalDevices_organization(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	organization := map[string]interface{}{"href": "/metal/v1/organizations/org"}
+	projectIDs := []string{
+		api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-devices-1", "organization": organization}),
+		api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-devices-2", "organization": organization}),
+		api.Seed(fakeapi.MetalProjects, map[string]interface{}{
+			"name":         "tfacc-devices-other",
+			"organization": map[string]interface{}{"href": "/metal/v1/organizations/otherOrg"},
+		}),
+	}
+	devices := []struct {
+		project  string
+		hostname string
+		metro    string
+	}{
+		{projectIDs[0], "web-1", "SV"},
+		{projectIDs[0], "db-1", "SV"},
+		{projectIDs[1], "web-2", "DA"},
+		{projectIDs[2], "web-3", "DA"},
+	}
+	for _, device := range devices {
+		api.Seed(fakeapi.MetalDevices, map[string]interface{}{
+			"hostname": device.hostname,
+			"state":    "active",
+			"plan":     map[string]interface{}{"slug": "c3.small.x86"},
+			"metro":    map[string]interface{}{"code": device.metro},
+			"project":  map[string]interface{}{"id": device.project},
+			"tags":     []interface{}{"web"},
+			"ip_addresses": []interface{}{
+				map[string]interface{}{"address": "10.0.0.1", "address_family": 4, "cidr": 31, "public": false, "management": true},
+			},
+		})
+	}
+	config := &Config{BaseURL: api.URL, AuthToken: "token"}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	r := dataSourceMetalDevices()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"organization_id": "org",
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "hostname", "values": []interface{}{"web-*"}, "match_by": "glob"},
+		},
+		"sort":     []interface{}{map[string]interface{}{"attribute": "hostname", "direction": "desc"}},
+		"group_by": "metro",
+	})
+	// when
+	diags := r.ReadContext(context.Background(), d, config)
+	// then
+	assert.False(t, diags.HasError(), "Error is not returned")
+	assert.Equal(t, 2, d.Get("result_count"), "Devices of organization projects are filtered")
+	assert.Equal(t, "web-2", d.Get("devices.0.hostname"), "Devices are sorted")
+	assert.Equal(t, "da", d.Get("devices.0.metro"), "Metro is flattened")
+	assert.Equal(t, "c3.small.x86", d.Get("devices.0.plan"), "Plan is flattened")
+	assert.Equal(t, "10.0.0.1", d.Get("devices.0.access_private_ipv4"), "Addresses are flattened")
+	assert.Equal(t, map[string]interface{}{"da": 1, "sv": 1}, d.Get("group_counts"), "Devices are grouped by metro")
+}
+
+func TestPushdownDeviceQuery(t *testing.T) {
+	// given
+	filters := []datalist.Filter{
+		{Attribute: "state", Values: []interface{}{"active"}, MatchBy: "in"},
+		{Attribute: "tags", Values: []interface{}{"web", "db"}, MatchBy: "in"},
+		{Attribute: "plan", Values: []interface{}{"c3.small.x86"}, MatchBy: "in"},
+		{Attribute: "hostname", Values: []interface{}{"web-1"}, MatchBy: "in"},
+	}
+	// when
+	params, remaining, err := pushdownDeviceQuery(nil, nil, filters, nil, 0)
+	noParams, _, _ := pushdownDeviceQuery(nil, nil, filters[:3], nil, 0)
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, map[string]interface{}{"search": "web-1"}, params, "Hostname is searched")
+	assert.Equal(t, filters, remaining, "Searched filters are applied to listed devices")
+	assert.Nil(t, noParams, "Filters of attributes not searched by the API are not pushed down")
+}
+
+func TestDataSourceMetalDevices_limit(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	projectID := api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-devices"})
+	for i := 0; i < 5; i++ {
+		api.Seed(fakeapi.MetalDevices, map[string]interface{}{
+			"hostname": fmt.Sprintf("web-%d", i),
+			"state":    "active",
+			"project":  map[string]interface{}{"id": projectID},
+		})
+	}
+	config := &Config{BaseURL: api.URL, AuthToken: "token"}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	r := dataSourceMetalDevices()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"project_id": projectID,
+		"limit":      2,
+	})
+	// when
+	diags := r.ReadContext(context.Background(), d, config)
+	// then
+	assert.False(t, diags.HasError(), "Error is not returned")
+	assert.Equal(t, 2, d.Get("result_count"), "Devices are limited")
+	var pages []string
+	for _, req := range api.Requests() {
+		if strings.HasSuffix(req.Path, "/devices") {
+			pages = append(pages, req.Query.Get("page")+"/"+req.Query.Get("per_page"))
+		}
+	}
+	assert.Equal(t, []string{"1/2"}, pages, "Only the first page of limit size is listed")
+}
@@ -80,7 +80,7 @@ func dataSourceMetalFacility() *schema.Resource {
 }
 
 func dataSourceMetalFacilityRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	code := d.Get("code").(string)
 
 	_, capacityOk := d.GetOk("capacity")
@@ -42,7 +42,7 @@ func dataSourceOperatingSystem() *schema.Resource {
 }
 
 func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	name, nameOK := d.GetOk("name")
 	distro, distroOK := d.GetOk("distro")
@@ -68,7 +68,7 @@ func dataSourceMetalPreCreatedIPBlock() *schema.Resource {
 
 func dataSourceMetalPreCreatedIPBlockRead(d *schema.ResourceData, meta interface{}) error {
 	var types string
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	projectID := d.Get("project_id").(string)
 
 	ipv := d.Get("address_family").(int)
@@ -64,7 +64,7 @@ func dataSourceMetalProjectSSHKey() *schema.Resource {
 }
 
 func dataSourceMetalProjectSSHKeyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	search := d.Get("search").(string)
 	id := d.Get("id").(string)
@@ -13,7 +13,7 @@ func TestAccDataSourceMetalProjectSSHKey_bySearch(t *testing.T) {
 	datasourceName := "data.equinix_metal_project_ssh_key.foobar"
 	keyName := acctest.RandomWithPrefix("tfacc-project-key")
 
-	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	publicKeyMaterial, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -50,7 +50,7 @@ func TestAccDataSourceMetalProjectSSHKey_bySearch(t *testing.T) {
 func TestAccDataSourceMetalProjectSSHKeyDataSource_yID(t *testing.T) {
 	datasourceName := "data.equinix_metal_project_ssh_key.foobar"
 
-	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	publicKeyMaterial, err := testSSHKeyPair(t, "key")
 	keyName := acctest.RandomWithPrefix("tfacc-project-key")
 
 	if err != nil {
@@ -72,7 +72,7 @@ func dataSourceNetworkAccountRead(ctx context.Context, d *schema.ResourceData, m
 	metro := d.Get(networkAccountSchemaNames["MetroCode"]).(string)
 	name := d.Get(networkAccountSchemaNames["Name"]).(string)
 	status := d.Get(networkAccountSchemaNames["Status"]).(string)
-	accounts, err := conf.ne.GetAccounts(metro)
+	accounts, err := conf.neClient(ctx).GetAccounts(metro)
 	if err != nil {
 		return diag.FromErr(err)
 	}
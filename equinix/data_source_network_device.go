@@ -610,10 +610,10 @@ func dataSourceNetworkDevice() *schema.Resource {
 	}
 }
 
-func getDeviceByName(deviceName string, conf *Config, validDeviceStateList *[]string) (*ne.Device, error) {
+func getDeviceByName(ctx context.Context, deviceName string, conf *Config, validDeviceStateList *[]string) (*ne.Device, error) {
 	var devices []ne.Device
 	err := error(nil)
-	devices, err = conf.ne.GetDevices(*validDeviceStateList)
+	devices, err = conf.neClient(ctx).GetDevices(*validDeviceStateList)
 	if err != nil {
 		return nil, fmt.Errorf("'devices: %v'", devices)
 	}
@@ -643,9 +643,9 @@ func dataSourceNetworkDeviceRead(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	if nameExists {
-		primary, err = getDeviceByName(name, conf, validDeviceStatusList)
+		primary, err = getDeviceByName(ctx, name, conf, validDeviceStatusList)
 	} else {
-		primary, err = conf.ne.GetDevice(uuid)
+		primary, err = conf.neClient(ctx).GetDevice(uuid)
 	}
 
 	if err != nil {
@@ -658,7 +658,7 @@ func dataSourceNetworkDeviceRead(ctx context.Context, d *schema.ResourceData, m
 	}
 	if ne.StringValue(primary.RedundantUUID) != "" {
 
-		secondary, err = conf.ne.GetDevice(ne.StringValue(primary.RedundantUUID))
+		secondary, err = conf.neClient(ctx).GetDevice(ne.StringValue(primary.RedundantUUID))
 		if err != nil {
 			return diag.Errorf("cannot fetch secondary network device due to '%v'", err)
 		}
@@ -115,7 +115,7 @@ func dataSourceNetworkDeviceSoftwareRead(ctx context.Context, d *schema.Resource
 	var diags diag.Diagnostics
 	typeCode := d.Get(networkDeviceSoftwareSchemaNames["DeviceTypeCode"]).(string)
 	pkgCodes := expandSetToStringList(d.Get(networkDeviceSoftwareSchemaNames["PackageCodes"]).(*schema.Set))
-	versions, err := conf.ne.GetDeviceSoftwareVersions(typeCode)
+	versions, err := conf.neClient(ctx).GetDeviceSoftwareVersions(typeCode)
 	if err != nil {
 		return diag.FromErr(err)
 	}
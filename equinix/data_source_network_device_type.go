@@ -83,7 +83,7 @@ func dataSourceNetworkDeviceType() *schema.Resource {
 func dataSourceNetworkDeviceTypeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	types, err := conf.ne.GetDeviceTypes()
+	types, err := conf.neClient(ctx).GetDeviceTypes()
 	name := d.Get(networkDeviceTypeSchemaNames["Name"]).(string)
 	vendor := d.Get(networkDeviceTypeSchemaNames["Vendor"]).(string)
 	category := d.Get(networkDeviceTypeSchemaNames["Category"]).(string)
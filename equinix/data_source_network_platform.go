@@ -107,7 +107,7 @@ func dataSourceNetworkDevicePlatformRead(ctx context.Context, d *schema.Resource
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	typeCode := d.Get(networkDevicePlatformSchemaNames["DeviceTypeCode"]).(string)
-	platforms, err := conf.ne.GetDevicePlatforms(typeCode)
+	platforms, err := conf.neClient(ctx).GetDevicePlatforms(typeCode)
 	if err != nil {
 		return diag.FromErr(err)
 	}
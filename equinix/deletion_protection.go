@@ -0,0 +1,111 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// deletionProtectionSchema returns schema of a flag that prevents deletion
+// of a resource, including replacement caused by changes of its arguments.
+// The flag has no default, so resources created before it was added have no
+// difference in their plans. Unset flag is read as false.
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Whether the resource is protected from deletion. The resource can't be deleted or replaced until the flag is disabled and applied. Defaults to false",
+	}
+}
+
+// checkDeletionProtection returns an error when a given resource can't be
+// deleted, either because its deletion_protection flag is enabled or because
+// it has a tag listed in provider protected_tags. Values are read from state,
+// so protection holds even when the resource was removed from configuration.
+func checkDeletionProtection(d *schema.ResourceData, meta interface{}, resourceType string, tags []string) error {
+	return deletionProtectionError(meta, resourceType, d.Id(), d.Get("deletion_protection").(bool), tags)
+}
+
+func deletionProtectionError(meta interface{}, resourceType, id string, protected bool, tags []string) error {
+	if protected {
+		return fmt.Errorf("%s %s is protected from deletion: set deletion_protection to false and apply before deleting or replacing it", resourceType, id)
+	}
+	if config, ok := meta.(*Config); ok {
+		for _, tag := range tags {
+			if isStringInSlice(tag, config.ProtectedTags) {
+				return fmt.Errorf("%s %s is protected from deletion by tag %q listed in provider protected_tags: remove the tag and apply before deleting or replacing it", resourceType, id, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// withDeletionProtection adds a check to CustomizeDiff of a given resource
+// that fails plans replacing a protected resource, so a replacement is not
+// created before deletion of the protected resource fails. Tags are read
+// from a given key, empty for resources without tags. Given conditions
+// report replacements forced by other CustomizeDiff functions.
+func withDeletionProtection(r *schema.Resource, resourceType, tagsKey string, replaced ...customdiff.ResourceConditionFunc) *schema.Resource {
+	forceNewKeys := forceNewSchemaKeys(r.Schema, "")
+	check := func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+		var changed []string
+		for _, key := range forceNewKeys {
+			if d.HasChange(key) {
+				changed = append(changed, key)
+			}
+		}
+		replacing := len(changed) > 0
+		for _, condition := range replaced {
+			replacing = replacing || condition(ctx, d, meta)
+		}
+		if !replacing {
+			return nil
+		}
+		protected, _ := d.GetChange("deletion_protection")
+		var tags []string
+		if tagsKey != "" {
+			oldTags, _ := d.GetChange(tagsKey)
+			if list, ok := oldTags.([]interface{}); ok {
+				tags = convertStringArr(list)
+			}
+		}
+		if err := deletionProtectionError(meta, resourceType, d.Id(), protected.(bool), tags); err != nil {
+			if len(changed) > 0 {
+				return fmt.Errorf("%s, changes of %s force replacement", err, strings.Join(changed, ", "))
+			}
+			return err
+		}
+		return nil
+	}
+	if r.CustomizeDiff == nil {
+		r.CustomizeDiff = check
+	} else {
+		r.CustomizeDiff = customdiff.Sequence(r.CustomizeDiff, check)
+	}
+	return r
+}
+
+// forceNewSchemaKeys returns sorted keys of ForceNew attributes of a given
+// schema, including attributes of nested single item blocks
+func forceNewSchemaKeys(s map[string]*schema.Schema, prefix string) []string {
+	var keys []string
+	for name, attr := range s {
+		key := prefix + name
+		if attr.ForceNew {
+			keys = append(keys, key)
+			continue
+		}
+		if nested, ok := attr.Elem.(*schema.Resource); ok && attr.Type == schema.TypeList && attr.MaxItems == 1 {
+			keys = append(keys, forceNewSchemaKeys(nested.Schema, key+".0.")...)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,161 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	// given
+	protected := schema.TestResourceDataRaw(t, resourceMetalVlan().Schema, map[string]interface{}{
+		"project_id":          "project",
+		"deletion_protection": true,
+	})
+	protected.SetId("protected")
+	unprotected := schema.TestResourceDataRaw(t, resourceMetalVlan().Schema, map[string]interface{}{
+		"project_id": "project",
+	})
+	unprotected.SetId("unprotected")
+	config := &Config{ProtectedTags: []string{"production"}}
+	// when
+	flagErr := checkDeletionProtection(protected, config, "equinix_metal_vlan", nil)
+	tagErr := checkDeletionProtection(unprotected, config, "equinix_metal_vlan", []string{"team:networking", "production"})
+	unprotectedErr := checkDeletionProtection(unprotected, config, "equinix_metal_vlan", []string{"team:networking"})
+	// then
+	assert.NotNil(t, flagErr, "Error is returned for resource with deletion_protection")
+	assert.Contains(t, flagErr.Error(), "deletion_protection", "Error explains how to remove protection")
+	assert.NotNil(t, tagErr, "Error is returned for resource with protected tag")
+	assert.Contains(t, tagErr.Error(), `"production"`, "Error names protected tag")
+	assert.Nil(t, unprotectedErr, "Error is not returned for unprotected resource")
+}
+
+func TestWithDeletionProtection_plan(t *testing.T) {
+	// given
+	r := resourceMetalVlan()
+	state := func(protected string) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "vlan",
+			Attributes: map[string]string{
+				"id":                  "vlan",
+				"project_id":          "project",
+				"metro":               "sv",
+				"description":         "test",
+				"deletion_protection": protected,
+			},
+		}
+	}
+	replacing := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id":          "project",
+		"metro":               "da",
+		"description":         "test",
+		"deletion_protection": false,
+	})
+	updating := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id":          "project",
+		"metro":               "sv",
+		"description":         "test",
+		"deletion_protection": false,
+	})
+	config := &Config{}
+	// when
+	_, protectedErr := r.Diff(context.Background(), state("true"), replacing, config)
+	_, unprotectedErr := r.Diff(context.Background(), state("false"), replacing, config)
+	_, updateErr := r.Diff(context.Background(), state("true"), updating, config)
+	// then
+	assert.NotNil(t, protectedErr, "Replacement of protected resource fails at plan")
+	assert.Contains(t, protectedErr.Error(), "changes of metro force replacement", "Error names replacing changes")
+	assert.Nil(t, unprotectedErr, "Replacement of unprotected resource is planned")
+	assert.Nil(t, updateErr, "Disabling protection in place is planned")
+}
+
+func TestWithDeletionProtection_planProtectedTag(t *testing.T) {
+	// given
+	r := resourceMetalReservedIPBlock()
+	state := &terraform.InstanceState{
+		ID: "block",
+		Attributes: map[string]string{
+			"id":             "block",
+			"project_id":     "project",
+			"metro":          "sv",
+			"type":           "public_ipv4",
+			"quantity":       "1",
+			"tags.#":         "1",
+			"tags.0":         "production",
+			"tags_all.#":     "1",
+			"tags_all.0":     "production",
+			"wait_for_state": "created",
+		},
+	}
+	replacing := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id": "project",
+		"metro":      "sv",
+		"type":       "public_ipv4",
+		"quantity":   2,
+		"tags":       []interface{}{"production"},
+	})
+	config := &Config{ProtectedTags: []string{"production"}}
+	// when
+	_, err := r.Diff(context.Background(), state, replacing, config)
+	// then
+	assert.NotNil(t, err, "Replacement of resource with protected tag fails at plan")
+	assert.Contains(t, err.Error(), `"production"`, "Error names protected tag")
+}
+
+func TestWithDeletionProtection_planDeviceReinstall(t *testing.T) {
+	// given
+	r := resourceMetalDevice()
+	state := &terraform.InstanceState{
+		ID: "device",
+		Attributes: map[string]string{
+			"id":                  "device",
+			"project_id":          "project",
+			"hostname":            "test",
+			"plan":                "c3.small.x86",
+			"metro":               "sv",
+			"operating_system":    "ubuntu_20_04",
+			"billing_cycle":       "hourly",
+			"deletion_protection": "true",
+		},
+	}
+	replacing := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id":          "project",
+		"hostname":            "test",
+		"plan":                "c3.small.x86",
+		"metro":               "sv",
+		"operating_system":    "ubuntu_22_04",
+		"billing_cycle":       "hourly",
+		"deletion_protection": true,
+	})
+	// when
+	_, err := r.Diff(context.Background(), state, replacing, &Config{})
+	// then
+	assert.NotNil(t, err, "Replacement by reinstall of protected device fails at plan")
+}
+
+func TestDeletionProtectionSchema_existingState(t *testing.T) {
+	// given
+	r := resourceMetalVlan()
+	state := &terraform.InstanceState{
+		ID: "vlan",
+		Attributes: map[string]string{
+			"id":          "vlan",
+			"project_id":  "project",
+			"metro":       "sv",
+			"description": "test",
+		},
+	}
+	unchanged := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id":  "project",
+		"metro":       "sv",
+		"description": "test",
+	})
+	// when
+	diff, err := r.Diff(context.Background(), state, unchanged, &Config{})
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.True(t, diff == nil || diff.Empty(), "State without deletion_protection has no difference")
+}
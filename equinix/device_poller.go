@@ -0,0 +1,117 @@
+package equinix
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+)
+
+const (
+	// devicePollInterval is the minimum time between requests listing
+	// devices of a single project to check their status
+	devicePollInterval = 5 * time.Second
+
+	// devicePollListThreshold is the number of devices of a single project
+	// waited for at the same time, from which their status is checked by
+	// listing devices of the project instead of fetching each device
+	devicePollListThreshold = 3
+)
+
+// devicePoller checks status of Equinix Metal devices waited for by
+// resources. When enough devices of the same project are waited for, status
+// checks are batched into a single list request per poll interval and the
+// result is shared by all waiting resources, so concurrent device creation
+// does not send a request per device and poll.
+type devicePoller struct {
+	client   *packngo.Client
+	interval time.Duration
+
+	mu       sync.Mutex
+	projects map[string]*projectDevicesPoll
+}
+
+// projectDevicesPoll is the last listing of devices in a project
+type projectDevicesPoll struct {
+	// waiters is the number of devices waited for, guarded by devicePoller
+	waiters int
+
+	mu      sync.Mutex
+	polled  time.Time
+	devices map[string]*packngo.Device
+}
+
+func newDevicePoller(client *packngo.Client, interval time.Duration) *devicePoller {
+	return &devicePoller{
+		client:   client,
+		interval: interval,
+		projects: make(map[string]*projectDevicesPoll),
+	}
+}
+
+// waiting registers a waiter of a device in a given project and returns
+// function that unregisters it, once the waiter stops checking the device.
+// Waiters of devices of unknown project are not registered, as their
+// devices are always fetched individually. Projects are forgotten when
+// their last waiter is unregistered.
+func (p *devicePoller) waiting(projectID string) func() {
+	if projectID == "" {
+		return func() {}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	poll, ok := p.projects[projectID]
+	if !ok {
+		poll = &projectDevicesPoll{}
+		p.projects[projectID] = poll
+	}
+	poll.waiters++
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if poll.waiters--; poll.waiters == 0 {
+			delete(p.projects, projectID)
+		}
+	}
+}
+
+// device returns current state of a given device. Devices of projects with
+// fewer waiters than devicePollListThreshold, devices that are not in the
+// last listing of their project, i.e. because they were created since, or
+// devices of unknown project are fetched individually.
+func (p *devicePoller) device(projectID, deviceID string) (*packngo.Device, error) {
+	p.mu.Lock()
+	poll, ok := p.projects[projectID]
+	batched := ok && poll.waiters >= devicePollListThreshold
+	p.mu.Unlock()
+	if batched {
+		if device := p.listed(projectID, poll, deviceID); device != nil {
+			return device, nil
+		}
+	}
+	device, _, err := p.client.Devices.Get(deviceID, nil)
+	return device, err
+}
+
+// listed returns device from project listing, which is refreshed when it is
+// older than poll interval. Concurrent callers wait for a single refresh.
+func (p *devicePoller) listed(projectID string, poll *projectDevicesPoll, deviceID string) *packngo.Device {
+	poll.mu.Lock()
+	defer poll.mu.Unlock()
+	if time.Since(poll.polled) >= p.interval {
+		poll.polled = time.Now()
+		poll.devices = nil
+		devices, _, err := p.client.Devices.List(projectID, nil)
+		if err != nil {
+			// waiters fall back to fetching their devices until next poll
+			log.Printf("[WARN] Failed to list devices of project %s to poll their status: %s", projectID, err)
+			return nil
+		}
+		poll.devices = make(map[string]*packngo.Device, len(devices))
+		for i := range devices {
+			poll.devices[devices[i].ID] = &devices[i]
+		}
+	}
+	return poll.devices[deviceID]
+}
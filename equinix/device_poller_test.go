@@ -0,0 +1,133 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevicePoller_batch(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	projectID := api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-poller"})
+	deviceIDs := make([]string, 10)
+	for i := range deviceIDs {
+		deviceIDs[i] = api.Seed(fakeapi.MetalDevices, map[string]interface{}{
+			"state":   "provisioning",
+			"project": map[string]interface{}{"id": projectID},
+		})
+	}
+	config := &Config{BaseURL: api.URL, AuthToken: "token"}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	poller := config.metalDevicePoller()
+	states := make([]string, len(deviceIDs))
+	for range deviceIDs {
+		defer poller.waiting(projectID)()
+	}
+	// when
+	var wg sync.WaitGroup
+	for i, id := range deviceIDs {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			if device, err := poller.device(projectID, id); err == nil {
+				states[i] = device.State
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	// then
+	lists, gets := 0, 0
+	for _, req := range api.Requests() {
+		if req.Method != http.MethodGet {
+			continue
+		}
+		switch req.Path {
+		case "/metal/v1/projects/" + projectID + "/devices":
+			lists++
+		default:
+			gets++
+		}
+	}
+	assert.Equal(t, 1, lists, "Devices of a project are listed once per interval")
+	assert.Equal(t, 0, gets, "Listed devices are not fetched individually")
+	for _, state := range states {
+		assert.Equal(t, "provisioning", state, "Every waiter receives device state")
+	}
+}
+
+func TestDevicePoller_unlistedDevice(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	projectID := api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-poller"})
+	deviceID := api.Seed(fakeapi.MetalDevices, map[string]interface{}{"state": "active"})
+	config := &Config{BaseURL: api.URL, AuthToken: "token"}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	// when
+	device, err := config.metalDevicePoller().device(projectID, deviceID)
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, "active", device.State, "Device missing in project listing is fetched")
+}
+
+func TestDevicePoller_singleWaiter(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	defer func(delay time.Duration) { deviceWaitDelay = delay }(deviceWaitDelay)
+	deviceWaitDelay = 0
+	projectID := api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-poller"})
+	for i := 0; i < 10; i++ {
+		api.Seed(fakeapi.MetalDevices, map[string]interface{}{
+			"state":   "active",
+			"project": map[string]interface{}{"id": projectID},
+		})
+	}
+	deviceID := api.Seed(fakeapi.MetalDevices, map[string]interface{}{
+		"state":   "active",
+		"project": map[string]interface{}{"id": projectID},
+	})
+	config := testFakeAPIConfig(t, api.URL)
+	d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{"project_id": projectID})
+	d.SetId(deviceID)
+	// when
+	state, err := waitForDeviceAttribute(context.Background(), d, []string{"active", "failed"}, []string{"queued", "provisioning"}, "state", config)
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, "active", state, "Device state is returned")
+	var paths []string
+	for _, req := range api.Requests() {
+		paths = append(paths, req.Path)
+	}
+	assert.Equal(t, []string{"/metal/v1/devices/" + deviceID}, paths, "Single waited device is fetched instead of listing the project")
+}
+
+func TestDevicePoller_waiting(t *testing.T) {
+	// given
+	poller := newDevicePoller(nil, devicePollInterval)
+	// when
+	doneUnknown := poller.waiting("")
+	doneFirst := poller.waiting("project")
+	doneSecond := poller.waiting("project")
+	registered := len(poller.projects)
+	doneFirst()
+	afterFirst := len(poller.projects)
+	doneSecond()
+	doneUnknown()
+	// then
+	assert.Equal(t, 1, registered, "Waiters of unknown project are not registered")
+	assert.Equal(t, 1, afterFirst, "Project is kept while it has waiters")
+	assert.Empty(t, poller.projects, "Project is forgotten when its last waiter leaves")
+}
@@ -1,21 +1,52 @@
 package equinix
 
 import (
+	"crypto/ed25519"
+	"crypto/sha256"
 	"fmt"
+	"math/rand"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"golang.org/x/crypto/ssh"
 )
 
-const tstResourcePrefix = "tfacc"
+const (
+	tstResourcePrefix = "tfacc"
+	tstRecorderSeed   = 1
+)
 
 func TestMain(m *testing.M) {
+	if recorderMode() != "" {
+		// recorded interactions are matched by request body, so randomized
+		// resource names need to be the same when recording and replaying
+		rand.Seed(tstRecorderSeed)
+	}
 	resource.TestMain(m)
 }
 
+// testSSHKeyPair returns public key material for acceptance tests. Keys are
+// generated with crypto/rand, so with recorder enabled they are derived from
+// the recorder seed, test name and given key name instead, to match recorded
+// request bodies on replay.
+func testSSHKeyPair(t *testing.T, name string) (string, error) {
+	if recorderMode() == "" {
+		publicKey, _, err := acctest.RandSSHKeyPair("")
+		return publicKey, err
+	}
+	seed := sha256.Sum256([]byte(fmt.Sprintf("%d/%s/%s", tstRecorderSeed, t.Name(), name)))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	publicKey, err := ssh.NewPublicKey(privateKey.Public())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))), nil
+}
+
 func sharedConfigForRegion(region string) (*Config, error) {
 	endpoint := getFromEnvDefault(endpointEnvVar, DefaultBaseURL)
 	clientToken := getFromEnvDefault(clientTokenEnvVar, "")
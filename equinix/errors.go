@@ -1,12 +1,16 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sort"
 	"strings"
 
+	"github.com/equinix/rest-go"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/packethost/packngo"
@@ -25,8 +29,13 @@ func friendlyError(err error) error {
 		er := &ErrorResponse{
 			StatusCode: resp.StatusCode,
 			Errors:     errors,
+			Service:    serviceMetal,
+		}
+		for _, message := range errors {
+			er.Details = append(er.Details, APIErrorDetail{Message: message})
 		}
 		respHead := resp.Header
+		er.RequestID = respHead.Get(requestIDHeader)
 
 		// this checks if the error comes from API (and not from cache/LB)
 		if len(errors) > 0 {
@@ -68,10 +77,26 @@ func (e Errors) Error() string {
 	return strings.Join(e, "; ")
 }
 
+// ErrorResponse is a normalized error of Equinix Metal, Fabric and Network
+// Edge APIs
 type ErrorResponse struct {
 	StatusCode int
 	Errors
 	IsAPIError bool
+	// RequestID is a value of X-Request-Id response header, when available
+	RequestID string
+	// Details are application errors with codes and related properties
+	Details []APIErrorDetail
+	// Service is a name of the service that returned the error, empty for
+	// Equinix Fabric and Network Edge errors, as they share the same client
+	Service string
+}
+
+// APIErrorDetail is a single application error reported by the API
+type APIErrorDetail struct {
+	Code     string
+	Message  string
+	Property string
 }
 
 func (er *ErrorResponse) Error() string {
@@ -83,9 +108,244 @@ func (er *ErrorResponse) Error() string {
 		ret += fmt.Sprintf("HTTP %d ", er.StatusCode)
 	}
 	ret += er.Errors.Error()
+	if er.RequestID != "" {
+		ret += fmt.Sprintf(" (request ID %s)", er.RequestID)
+	}
+	for _, hint := range er.hints() {
+		ret += "\n" + hint
+	}
 	return ret
 }
 
+// hasCode checks if any of application errors has a given code
+func (er *ErrorResponse) hasCode(code string) bool {
+	for _, detail := range er.Details {
+		if detail.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hints returns remediation hints of known error statuses
+func (er *ErrorResponse) hints() []string {
+	statusHints := restErrorStatusHints
+	if er.Service == serviceMetal {
+		statusHints = metalErrorStatusHints
+	}
+	if hint, ok := statusHints[er.StatusCode]; ok {
+		return []string{hint}
+	}
+	if hint, ok := apiErrorStatusHints[er.StatusCode]; ok {
+		return []string{hint}
+	}
+	return nil
+}
+
+// apiErrorStatusHints are remediation hints of error statuses shared by
+// Equinix Metal, Fabric and Network Edge APIs
+var apiErrorStatusHints = map[int]string{
+	http.StatusTooManyRequests: "The API rate limit was still exceeded after retries. Limit requests with the rate_limit argument, lower Terraform parallelism or raise max_retries and max_retry_wait_seconds.",
+}
+
+// metalErrorStatusHints are remediation hints of Equinix Metal API error
+// statuses
+var metalErrorStatusHints = map[int]string{
+	http.StatusUnauthorized: "Equinix Metal rejected the auth token. Verify the auth_token argument or METAL_AUTH_TOKEN environment variable.",
+	http.StatusForbidden:    "The auth token is missing Equinix Metal scope or access to the project. Use a user or project API key with read/write permissions.",
+}
+
+// restErrorStatusHints are remediation hints of Equinix Fabric and
+// Network Edge API error statuses
+var restErrorStatusHints = map[int]string{
+	http.StatusUnauthorized: "The API token is invalid or expired. Verify the client_id and client_secret or token arguments.",
+	http.StatusForbidden:    "The API client is missing permissions for Equinix Fabric or Network Edge. Verify the application assigned to client_id in the developer portal.",
+}
+
+// apiError normalizes Equinix Fabric and Network Edge REST errors and
+// Equinix Metal errors
+func apiError(err error) (*ErrorResponse, bool) {
+	switch e := err.(type) {
+	case *ErrorResponse:
+		return e, true
+	case *packngo.ErrorResponse:
+		if e.Response == nil {
+			return nil, false
+		}
+		er, ok := friendlyError(e).(*ErrorResponse)
+		return er, ok
+	case rest.Error:
+		er := &ErrorResponse{
+			StatusCode: e.HTTPCode,
+			IsAPIError: e.HTTPCode != 0,
+		}
+		for _, appErr := range e.ApplicationErrors {
+			message := appErr.Message
+			if appErr.Code != "" {
+				message = fmt.Sprintf("%s: %s", appErr.Code, appErr.Message)
+			}
+			er.Errors = append(er.Errors, message)
+			er.Details = append(er.Details, APIErrorDetail{
+				Code:     appErr.Code,
+				Message:  appErr.Message,
+				Property: appErr.Property,
+			})
+		}
+		if len(er.Errors) == 0 {
+			er.Errors = Errors{e.Message}
+		}
+		return er, true
+	}
+	return nil, false
+}
+
+// hasAPIErrorCode checks if given error is an API error with a given
+// application error code
+func hasAPIErrorCode(err error, code string) bool {
+	er, ok := apiError(err)
+	return ok && er.hasCode(code)
+}
+
+// diagFromAPIError converts given error of an operation with given context
+// to diagnostics. API errors are normalized and enriched with request ID and
+// remediation hints. Errors of Equinix Fabric and Network Edge do not carry
+// request ID, it is looked up in the request ID recorder of the context. Application
+// errors related to a property are reported on the matching attribute,
+// properties are matched case insensitively with the keys of given schema
+// names map. Equinix Metal errors have no properties, they are reported on
+// the attribute whose key starts the error message, i.e. "Hostname can't be
+// blank".
+func diagFromAPIError(ctx context.Context, err error, schemaNames map[string]string) diag.Diagnostics {
+	er, ok := apiError(err)
+	if !ok {
+		return diag.FromErr(err)
+	}
+	var detail []string
+	if er.StatusCode != 0 {
+		detail = append(detail, fmt.Sprintf("HTTP status: %d", er.StatusCode))
+	}
+	requestID := er.RequestID
+	if requestID == "" && er.StatusCode != 0 {
+		requestID = requestIDOfStatus(ctx, er.StatusCode)
+	}
+	if requestID != "" {
+		detail = append(detail, fmt.Sprintf("Request ID: %s", requestID))
+	}
+	detail = append(detail, er.hints()...)
+	var diags diag.Diagnostics
+	var unattributed Errors
+	for i, message := range er.Errors {
+		var attribute string
+		if i < len(er.Details) && er.Details[i].Property != "" {
+			attribute = schemaNameOfProperty(schemaNames, er.Details[i].Property)
+		} else if er.Service == serviceMetal {
+			attribute = schemaNameOfMessage(schemaNames, message)
+		}
+		if attribute == "" {
+			unattributed = append(unattributed, message)
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       message,
+			Detail:        strings.Join(detail, "\n"),
+			AttributePath: cty.GetAttrPath(attribute),
+		})
+	}
+	if len(unattributed) > 0 || len(diags) == 0 {
+		summary := unattributed.Error()
+		if er.IsAPIError {
+			summary = "API Error: " + summary
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   strings.Join(detail, "\n"),
+		})
+	}
+	return diags
+}
+
+func schemaNameOfProperty(schemaNames map[string]string, property string) string {
+	for field, name := range schemaNames {
+		if strings.EqualFold(field, property) {
+			return name
+		}
+	}
+	return ""
+}
+
+// schemaNameOfMessage returns schema name of the longest key of given schema
+// names map that starts a given message, followed by a space
+func schemaNameOfMessage(schemaNames map[string]string, message string) string {
+	var key, name string
+	for field, n := range schemaNames {
+		if len(field) > len(key) && len(message) > len(field) && message[len(field)] == ' ' &&
+			strings.EqualFold(field, message[:len(field)]) {
+			key, name = field, n
+		}
+	}
+	return name
+}
+
+// withMetalDiagnostics replaces legacy create and update functions of
+// Equinix Metal resource with context aware ones that report API errors as
+// diagnostics on related attributes. Schema names are keyed by attribute
+// names with spaces instead of underscores, as they are written in error
+// messages. Context aware functions created with metalContextCRUD report
+// errors the same way.
+func withMetalDiagnostics(r *schema.Resource) *schema.Resource {
+	schemaNames := make(map[string]string, len(r.Schema))
+	for name := range r.Schema {
+		schemaNames[strings.ReplaceAll(name, "_", " ")] = name
+	}
+	diagnostics := func(f func(*schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := f(d, meta); err != nil {
+				return diagFromAPIError(ctx, err, schemaNames)
+			}
+			return nil
+		}
+	}
+	withSchemaNames := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return f(context.WithValue(ctx, metalSchemaNamesCtxKey{}, schemaNames), d, meta)
+		}
+	}
+	if r.Create != nil {
+		r.CreateContext = diagnostics(r.Create)
+		r.Create = nil
+	} else if r.CreateContext != nil {
+		r.CreateContext = withSchemaNames(r.CreateContext)
+	}
+	if r.Update != nil {
+		r.UpdateContext = diagnostics(r.Update)
+		r.Update = nil
+	} else if r.UpdateContext != nil {
+		r.UpdateContext = withSchemaNames(r.UpdateContext)
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = withSchemaNames(r.DeleteContext)
+	}
+	return r
+}
+
+type metalSchemaNamesCtxKey struct{}
+
+// metalContextCRUD converts create, update or delete function of Equinix
+// Metal resource that needs context of the operation, i.e. to wait for
+// asynchronous changes, to context aware function. Errors are reported on
+// related attributes when the resource is wrapped with withMetalDiagnostics.
+func metalContextCRUD(f func(context.Context, *schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if err := f(ctx, d, meta); err != nil {
+			schemaNames, _ := ctx.Value(metalSchemaNamesCtxKey{}).(map[string]string)
+			return diagFromAPIError(ctx, err, schemaNames)
+		}
+		return nil
+	}
+}
+
 // setMap sets the map of values to ResourceData, checking and returning the
 // errors. Typically d.Set is not error checked. This helper makes checking
 // those errors less tedious. Because this works with a map, the order of the
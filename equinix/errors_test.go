@@ -0,0 +1,182 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/equinix/rest-go"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors_apiErrorRest(t *testing.T) {
+	// given
+	err := rest.Error{
+		HTTPCode: http.StatusBadRequest,
+		Message:  "Bad Request",
+		ApplicationErrors: []rest.ApplicationError{
+			{Code: "IC-LAYER2-4021", Message: "Connection already deleted"},
+			{Code: "IC-LAYER2-1001", Message: "Invalid speed", Property: "speed"},
+		},
+	}
+	// when
+	er, ok := apiError(err)
+	diags := diagFromAPIError(context.Background(), err, ecxL2ConnectionSchemaNames)
+	// then
+	assert.True(t, ok, "Error is normalized")
+	assert.Equal(t, http.StatusBadRequest, er.StatusCode)
+	assert.True(t, hasAPIErrorCode(err, "IC-LAYER2-4021"), "Error code is found")
+	assert.False(t, hasAPIErrorCode(err, "IC-PROFILE-004"), "Other error code is not found")
+	assert.Len(t, diags, 2, "Attributed and unattributed errors are reported separately")
+	assert.Equal(t, "IC-LAYER2-1001: Invalid speed", diags[0].Summary)
+	assert.Equal(t, cty.GetAttrPath("speed"), diags[0].AttributePath, "Property is mapped to attribute")
+	assert.Equal(t, "API Error: IC-LAYER2-4021: Connection already deleted", diags[1].Summary)
+	assert.NotContains(t, diags[1].Detail, "Request ID", "Unknown request ID is not reported")
+}
+
+func TestErrors_apiErrorMetal(t *testing.T) {
+	// given
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(requestIDHeader, "req-123")
+	err := &packngo.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusForbidden, Header: header},
+		Errors:   []string{"You are not authorized to view this project"},
+	}
+	// when
+	er, ok := apiError(err)
+	diags := diagFromAPIError(context.Background(), err, nil)
+	// then
+	assert.True(t, ok, "Error is normalized")
+	assert.True(t, er.IsAPIError)
+	assert.Equal(t, "req-123", er.RequestID)
+	assert.True(t, isForbidden(er), "Normalized error is forbidden")
+	assert.Len(t, diags, 1)
+	assert.True(t, strings.Contains(diags[0].Detail, "Request ID: req-123"), "Request ID is reported")
+	assert.Contains(t, diags[0].Detail, metalErrorStatusHints[http.StatusForbidden], "Status hint is added")
+	assert.Contains(t, er.Error(), "(request ID req-123)")
+}
+
+func TestErrors_diagFromAPIErrorRestRequestID(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(requestIDHeader, "req-"+r.URL.Query().Get("n"))
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`[{"errorCode":"IC-LAYER2-1001","errorMessage":"Invalid speed","property":"speed"}]`))
+	}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: newRequestIDTransport(http.DefaultTransport)}
+	firstCtx := withRequestIDRecorder(context.Background())
+	secondCtx := withRequestIDRecorder(context.Background())
+	first := rest.NewClient(firstCtx, server.URL, httpClient)
+	second := rest.NewClient(secondCtx, server.URL, httpClient)
+	// when
+	firstErr := first.Execute(first.R().SetQueryParam("n", "1"), http.MethodGet, "/connections")
+	secondErr := second.Execute(second.R().SetQueryParam("n", "2"), http.MethodGet, "/connections")
+	firstDiags := diagFromAPIError(firstCtx, firstErr, ecxL2ConnectionSchemaNames)
+	secondDiags := diagFromAPIError(secondCtx, secondErr, ecxL2ConnectionSchemaNames)
+	// then
+	assert.Equal(t, firstErr, secondErr, "Errors are identical")
+	assert.Contains(t, firstDiags[0].Detail, "Request ID: req-1", "Request ID of first operation is reported")
+	assert.Contains(t, secondDiags[0].Detail, "Request ID: req-2", "Request ID of second operation is reported")
+}
+
+func TestErrors_diagFromAPIErrorStatusHint(t *testing.T) {
+	// given
+	err := rest.Error{HTTPCode: http.StatusTooManyRequests, Message: "Too Many Requests"}
+	// when
+	diags := diagFromAPIError(context.Background(), err, nil)
+	// then
+	assert.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Detail, apiErrorStatusHints[http.StatusTooManyRequests], "Shared status hint is added")
+}
+
+func TestErrors_diagFromAPIErrorMetalAttribute(t *testing.T) {
+	// given
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	err := &packngo.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusUnprocessableEntity, Header: header},
+		Errors:   []string{"Operating system is not available for the plan", "Project is suspended"},
+	}
+	schemaNames := map[string]string{
+		"operating system": "operating_system",
+		"plan":             "plan",
+	}
+	// when
+	diags := diagFromAPIError(context.Background(), err, schemaNames)
+	// then
+	assert.Len(t, diags, 2, "Attributed and unattributed errors are reported separately")
+	assert.Equal(t, cty.GetAttrPath("operating_system"), diags[0].AttributePath, "Message is mapped to attribute")
+	assert.Equal(t, "Project is suspended", diags[1].Summary)
+}
+
+func TestErrors_withMetalDiagnostics(t *testing.T) {
+	// given
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"hostname": {Type: schema.TypeString, Optional: true, ForceNew: true},
+		},
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return friendlyError(&packngo.ErrorResponse{
+				Response: &http.Response{StatusCode: http.StatusUnprocessableEntity, Header: header},
+				Errors:   []string{"Hostname can't be blank"},
+			})
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+	}
+	// when
+	withMetalDiagnostics(r)
+	diags := r.CreateContext(context.Background(), r.TestResourceData(), nil)
+	// then
+	assert.Nil(t, r.Create, "Legacy create function is removed")
+	assert.Nil(t, r.InternalValidate(nil, true), "Resource is valid")
+	assert.Len(t, diags, 1)
+	assert.Equal(t, cty.GetAttrPath("hostname"), diags[0].AttributePath, "Message is mapped to attribute")
+}
+
+func TestErrors_metalContextCRUD(t *testing.T) {
+	// given
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	type ctxKey struct{}
+	var waiterCtx context.Context
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"hostname": {Type: schema.TypeString, Optional: true, ForceNew: true},
+		},
+		CreateContext: metalContextCRUD(func(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+			waiterCtx = ctx
+			return friendlyError(&packngo.ErrorResponse{
+				Response: &http.Response{StatusCode: http.StatusUnprocessableEntity, Header: header},
+				Errors:   []string{"Hostname can't be blank"},
+			})
+		}),
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+	}
+	// when
+	withMetalDiagnostics(r)
+	diags := r.CreateContext(context.WithValue(context.Background(), ctxKey{}, "operation"), r.TestResourceData(), nil)
+	// then
+	assert.Equal(t, "operation", waiterCtx.Value(ctxKey{}), "Context of the operation is passed")
+	assert.Len(t, diags, 1)
+	assert.Equal(t, cty.GetAttrPath("hostname"), diags[0].AttributePath, "Message is mapped to attribute")
+}
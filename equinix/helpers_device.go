@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -23,6 +24,11 @@ const (
 var (
 	wgMap   = map[string]*sync.WaitGroup{}
 	wgMutex = sync.Mutex{}
+
+	// deviceWaitDelay is the time before the first check of device
+	// attribute waited for, changes requested just before are not visible
+	// in device status immediately
+	deviceWaitDelay = 10 * time.Second
 )
 
 func ifToIPCreateRequest(m interface{}) packngo.IPAddressCreateRequest {
@@ -141,16 +147,16 @@ func hwReservationStateRefreshFunc(client *packngo.Client, reservationId, instan
 	}
 }
 
-func waitUntilReservationProvisionable(client *packngo.Client, reservationId, instanceId string, delay, timeout, minTimeout time.Duration) error {
+func waitUntilReservationProvisionable(ctx context.Context, config *Config, reservationId, instanceId string, delay, timeout, minTimeout time.Duration) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{deprovisioning},
 		Target:     []string{provisionable, reprovisioned},
-		Refresh:    hwReservationStateRefreshFunc(client, reservationId, instanceId),
+		Refresh:    hwReservationStateRefreshFunc(config.metalClient(), reservationId, instanceId),
 		Timeout:    timeout,
 		Delay:      delay,
 		MinTimeout: minTimeout,
 	}
-	_, err := stateConf.WaitForState()
+	_, err := config.stateWaiter(ctx, "metal_hardware_reservation", stateConf).WaitForStateContext(ctx)
 	return err
 }
 
@@ -165,7 +171,7 @@ func getWaitForDeviceLock(deviceID string) *sync.WaitGroup {
 	return wg
 }
 
-func waitForDeviceAttribute(d *schema.ResourceData, targets []string, pending []string, attribute string, meta interface{}) (string, error) {
+func waitForDeviceAttribute(ctx context.Context, d *schema.ResourceData, targets []string, pending []string, attribute string, meta interface{}) (string, error) {
 	wg := getWaitForDeviceLock(d.Id())
 	wg.Wait()
 
@@ -183,12 +189,15 @@ func waitForDeviceAttribute(d *schema.ResourceData, targets []string, pending []
 		return "", fmt.Errorf("unsupported attr to wait for: %s", attribute)
 	}
 
+	poller := meta.(*Config).metalDevicePoller()
+	projectID := d.Get("project_id").(string)
+	defer poller.waiting(projectID)()
+
 	stateConf := &resource.StateChangeConf{
 		Pending: pending,
 		Target:  targets,
 		Refresh: func() (interface{}, string, error) {
-			client := meta.(*Config).metal
-			device, _, err := client.Devices.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project"}})
+			device, err := poller.device(projectID, d.Id())
 			if err == nil {
 				retAttrVal := device.State
 				if attribute == "network_type" {
@@ -200,11 +209,11 @@ func waitForDeviceAttribute(d *schema.ResourceData, targets []string, pending []
 			return "error", "error", err
 		},
 		Timeout:    60 * time.Minute,
-		Delay:      10 * time.Second,
+		Delay:      deviceWaitDelay,
 		MinTimeout: 3 * time.Second,
 	}
 
-	attrValRaw, err := stateConf.WaitForState()
+	attrValRaw, err := meta.(*Config).stateWaiter(ctx, "metal_device_"+attribute, stateConf).WaitForStateContext(ctx)
 
 	if v, ok := attrValRaw.(string); ok {
 		return v, err
@@ -214,14 +223,14 @@ func waitForDeviceAttribute(d *schema.ResourceData, targets []string, pending []
 }
 
 // powerOnAndWait Powers on the device and waits for it to be active.
-func powerOnAndWait(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func powerOnAndWait(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	_, err := client.Devices.PowerOn(d.Id())
 	if err != nil {
 		return friendlyError(err)
 	}
 
-	_, err = waitForDeviceAttribute(d, []string{"active", "failed"}, []string{"off"}, "state", client)
+	_, err = waitForDeviceAttribute(ctx, d, []string{"active", "failed"}, []string{"off"}, "state", meta)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -149,7 +150,7 @@ func Test_waitUntilReservationProvisionable(t *testing.T) {
 	// timeout * number of tests that reach timeout must be less than 30s (default go test timeout).
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := waitUntilReservationProvisionable(tt.args.meta, tt.args.reservationId, tt.args.instanceId, 50*time.Millisecond, 1*time.Second, 50*time.Millisecond); (err != nil) != tt.wantErr {
+			if err := waitUntilReservationProvisionable(context.Background(), &Config{metal: tt.args.meta}, tt.args.reservationId, tt.args.instanceId, 50*time.Millisecond, 1*time.Second, 50*time.Millisecond); (err != nil) != tt.wantErr {
 				t.Errorf("waitUntilReservationProvisionable() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
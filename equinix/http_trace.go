@@ -0,0 +1,166 @@
+package equinix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	httpTraceEnvVar    = "EQUINIX_HTTP_TRACE"
+	requestIDHeader    = "X-Request-Id"
+	jsonLinesFileFlags = os.O_APPEND | os.O_CREATE | os.O_WRONLY
+)
+
+// httpTraceEntry is a single, sanitized API call written to trace file
+// as JSON line
+type httpTraceEntry struct {
+	Time           time.Time   `json:"time"`
+	Service        string      `json:"service"`
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	Status         int         `json:"status,omitempty"`
+	LatencyMs      int64       `json:"latency_ms"`
+	RequestID      string      `json:"request_id,omitempty"`
+	Error          string      `json:"error,omitempty"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+}
+
+// jsonLinesWriter appends entries to a file as JSON lines, entries of
+// concurrent requests are never interleaved
+type jsonLinesWriter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var (
+	jsonLinesWritersMu sync.Mutex
+	jsonLinesWriters   = make(map[string]*jsonLinesWriter)
+)
+
+// openJSONLines returns writer of a given file. Writer is shared by
+// all API clients, so the file is opened only once.
+func openJSONLines(path string) (*jsonLinesWriter, error) {
+	jsonLinesWritersMu.Lock()
+	defer jsonLinesWritersMu.Unlock()
+	if w, ok := jsonLinesWriters[path]; ok {
+		return w, nil
+	}
+	f, err := os.OpenFile(path, jsonLinesFileFlags, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	w := &jsonLinesWriter{file: f}
+	jsonLinesWriters[path] = w
+	return w, nil
+}
+
+func (w *jsonLinesWriter) write(entry interface{}) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.file.Write(append(line, '\n'))
+	return err
+}
+
+// httpTraceTransport is a http.RoundTripper that writes every API call,
+// including retries, to a trace file. Authorization headers and sensitive
+// body values like passwords, license tokens or authentication keys
+// are redacted.
+type httpTraceTransport struct {
+	service string
+	writer  *jsonLinesWriter
+	next    http.RoundTripper
+}
+
+// newHTTPTraceTransport wraps given transport with tracing or returns
+// the transport as it is when trace file is not set
+func newHTTPTraceTransport(service, path string, next http.RoundTripper) (http.RoundTripper, error) {
+	if path == "" {
+		return next, nil
+	}
+	writer, err := openJSONLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open HTTP trace file: %s", err)
+	}
+	return &httpTraceTransport{
+		service: service,
+		writer:  writer,
+		next:    next,
+	}, nil
+}
+
+func (t *httpTraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	entry := &httpTraceEntry{
+		Time:          time.Now().UTC(),
+		Service:       t.service,
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		RequestHeader: redactHeader(req.Header),
+		RequestBody:   traceBody(reqBody),
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		var respBody []byte
+		respBody, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			resp = nil
+		} else {
+			resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+			entry.Status = resp.StatusCode
+			entry.RequestID = resp.Header.Get(requestIDHeader)
+			entry.ResponseHeader = redactHeader(resp.Header)
+			entry.ResponseBody = traceBody(respBody)
+		}
+	}
+	entry.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if werr := t.writer.write(entry); werr != nil {
+		log.Printf("[WARN] Failed to write HTTP trace of %s %s: %s", req.Method, req.URL, werr)
+	}
+	return resp, err
+}
+
+// redactHeader replaces values of sensitive headers, so their presence
+// is still visible in traces
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, h := range sensitiveHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(h)]; ok {
+			redacted.Set(h, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// traceBody returns sanitized JSON body. Other bodies, like multipart
+// license file uploads, are omitted as they cannot be redacted.
+func traceBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !json.Valid(body) {
+		return fmt.Sprintf("<%d bytes omitted>", len(body))
+	}
+	return sanitizeBody(body)
+}
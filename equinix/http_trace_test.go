@@ -0,0 +1,49 @@
+package equinix
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPTraceTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "req-123")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"uuid":"abc","licenseToken":"secretLicense"}`))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	transport, err := newHTTPTraceTransport(serviceNetworkEdge, path, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("cannot create trace transport: %s", err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/ne/v1/devices", strings.NewReader(`{"password":"secretPassword","name":"test"}`))
+	req.Header.Set("Authorization", "Bearer secretToken")
+	// when
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	// then
+	assert.Nil(t, err, "Request does not fail")
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, `{"uuid":"abc","licenseToken":"secretLicense"}`, string(body), "Response body is not altered")
+	trace, _ := ioutil.ReadFile(path)
+	assert.NotContains(t, string(trace), "secret", "Secrets are redacted")
+	entry := httpTraceEntry{}
+	assert.Nil(t, json.Unmarshal(trace, &entry), "Trace is a JSON line")
+	assert.Equal(t, serviceNetworkEdge, entry.Service)
+	assert.Equal(t, http.MethodPost, entry.Method)
+	assert.Equal(t, server.URL+"/ne/v1/devices", entry.URL)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, "req-123", entry.RequestID)
+	assert.Equal(t, redactedValue, entry.RequestHeader.Get("Authorization"))
+	assert.Equal(t, `{"name":"test","password":"REDACTED"}`, entry.RequestBody)
+}
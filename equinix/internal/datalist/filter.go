@@ -4,14 +4,16 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var (
-	matchByStringComparison = []string{"in", "re", "substring"}
+	matchByStringComparison = []string{"in", "re", "re_ignore_case", "substring", "glob"}
 	matchByNumberComparison = []string{"less_than", "less_than_or_equal", "greater_than", "greater_than_or_equal"}
+	matchByAnyComparison    = []string{"not_in", "exists"}
 )
 
 type commonFilter struct {
@@ -21,16 +23,16 @@ type commonFilter struct {
 	matchBy   string
 }
 
-func filterSchema(allowedAttributes []string) *schema.Schema {
+func filterSchema(recordSchema map[string]*schema.Schema) *schema.Schema {
 	return &schema.Schema{
 		Type: schema.TypeSet,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"attribute": {
 					Type:         schema.TypeString,
-					Description:  "The attribute used to filter. Filter attributes are case-sensitive",
+					Description:  "The attribute used to filter. Filter attributes are case-sensitive. Attributes of nested objects and values of maps are referenced with dotted paths, i.e. `ip_address.address` or `tags.env`",
 					Required:     true,
-					ValidateFunc: validation.StringInSlice(allowedAttributes, false),
+					ValidateFunc: validateFilterAttribute(recordSchema),
 				},
 				"values": {
 					Type:        schema.TypeList,
@@ -46,10 +48,10 @@ func filterSchema(allowedAttributes []string) *schema.Schema {
 				},
 				"match_by": {
 					Type:         schema.TypeString,
-					Description:  "The type of comparison to apply. One of: in (default), re, substring, less_than, less_than_or_equal, greater_than, greater_than_or_equal",
+					Description:  "The type of comparison to apply. One of: in (default), not_in, exists, re, re_ignore_case, substring, glob, less_than, less_than_or_equal, greater_than, greater_than_or_equal",
 					Optional:     true,
 					Default:      "in",
-					ValidateFunc: validation.StringInSlice(append(matchByNumberComparison, matchByStringComparison...), false),
+					ValidateFunc: validation.StringInSlice(append(append(matchByNumberComparison, matchByStringComparison...), matchByAnyComparison...), false),
 				},
 			},
 		},
@@ -58,6 +60,30 @@ func filterSchema(allowedAttributes []string) *schema.Schema {
 	}
 }
 
+// Returns a function validating that filter attribute is a path to
+// primitive values in record schema
+func validateFilterAttribute(recordSchema map[string]*schema.Schema) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		v, ok := i.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+		}
+		if _, err := lookupAttributeSchema(recordSchema, v); err != nil {
+			return nil, []error{fmt.Errorf("invalid value for %s: %s", k, err)}
+		}
+		return nil, nil
+	}
+}
+
+func isStringInSlice(needle string, haystack []string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
 func expandFilters(recordSchema map[string]*schema.Schema, rawFilters []interface{}) ([]commonFilter, error) {
 	expandedFilters := make([]commonFilter, len(rawFilters))
 
@@ -65,26 +91,35 @@ func expandFilters(recordSchema map[string]*schema.Schema, rawFilters []interfac
 		f := rawFilter.(map[string]interface{})
 
 		attr := f["attribute"].(string)
-		s, ok := recordSchema[attr]
-		if !ok {
-			return nil, fmt.Errorf("field '%s' does not exist in record schema", attr)
+		s, err := lookupAttributeSchema(recordSchema, attr)
+		if err != nil {
+			return nil, err
 		}
 
 		matchBy := "in"
-		if v, ok := f["match_by"].(string); ok {
+		if v, ok := f["match_by"].(string); ok && v != "" {
 			matchBy = v
 		}
 
+		switch {
+		case isStringInSlice(matchBy, matchByNumberComparison):
+			if s.Type != schema.TypeInt && s.Type != schema.TypeFloat {
+				return nil, fmt.Errorf("match_by '%s' works only with numeric field, '%s' is not numeric", matchBy, attr)
+			}
+		case matchBy != "in" && isStringInSlice(matchBy, matchByStringComparison):
+			if s.Type != schema.TypeString {
+				return nil, fmt.Errorf("match_by '%s' works only with string field, '%s' is not a string", matchBy, attr)
+			}
+		}
+
 		expandedFilterValues, err := expandFilterValues(f["values"].([]interface{}), s, matchBy)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, nc := range matchByNumberComparison {
-			if matchBy == nc {
-				if len(expandedFilterValues) != 1 {
-					return nil, fmt.Errorf("field '%s' works with only one value", matchBy)
-				}
+		if matchBy == "exists" || isStringInSlice(matchBy, matchByNumberComparison) {
+			if len(expandedFilterValues) != 1 {
+				return nil, fmt.Errorf("field '%s' works with only one value", matchBy)
 			}
 		}
 
@@ -128,19 +163,37 @@ func expandPrimitiveFilterValue(
 ) (interface{}, error) {
 	var expandedValue interface{}
 
+	if matchBy == "exists" {
+		boolValue, err := strconv.ParseBool(filterValue)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse value as bool: %s: %s", filterValue, err)
+		}
+		return boolValue, nil
+	}
+
 	switch fieldType {
 	case schema.TypeString:
 		switch matchBy {
-		case "in", "substring":
+		case "in", "not_in", "substring":
 			expandedValue = filterValue
-		case "re":
-			re, err := regexp.Compile(filterValue)
+		case "re", "re_ignore_case":
+			expr := filterValue
+			if matchBy == "re_ignore_case" {
+				expr = "(?i)" + expr
+			}
+			re, err := regexp.Compile(expr)
 			if err != nil {
 				return nil, fmt.Errorf("unable to parse value as regular expression: %s: %s", filterValue, err)
 			}
 			expandedValue = re
+		case "glob":
+			re, err := compileGlob(filterValue)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse value as glob pattern: %s: %s", filterValue, err)
+			}
+			expandedValue = re
 		default:
-			panic("unreachable")
+			return nil, fmt.Errorf("match_by '%s' does not work with string fields", matchBy)
 		}
 
 	case schema.TypeBool:
@@ -165,14 +218,35 @@ func expandPrimitiveFilterValue(
 		expandedValue = floatValue
 
 	default:
-		panic("unreachable")
+		return nil, fmt.Errorf("cannot filter on a non-primitive type")
 	}
 
 	return expandedValue, nil
 }
 
+// Compiles a glob pattern, where `*` matches any sequence of characters and
+// `?` matches a single character, into a regular expression matching whole
+// values.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
 // Takes the "raw" set of strings provided by the Terraform SDK and converts the values
-// into the actual Go types used for comparisons when filtering.
+// into the actual Go types used for comparisons when filtering. The field schema is
+// a schema of primitive values returned by lookupAttributeSchema.
 func expandFilterValues(
 	rawFilterValues []interface{},
 	fieldSchema *schema.Schema,
@@ -181,47 +255,72 @@ func expandFilterValues(
 	expandedFilterValues := make([]interface{}, len(rawFilterValues))
 
 	for i, rawFilterValue := range rawFilterValues {
-		filterValue := rawFilterValue.(string)
-		var expandedValue interface{}
-
-		if isPrimitiveType(fieldSchema.Type) {
-			ev, err := expandPrimitiveFilterValue(filterValue, fieldSchema.Type, matchBy)
-			if err != nil {
-				return nil, err
-			}
-			expandedValue = ev
-		} else {
-			if s, ok := fieldSchema.Elem.(*schema.Schema); ok {
-				if isPrimitiveType(s.Type) {
-					ev, err := expandPrimitiveFilterValue(filterValue, s.Type, matchBy)
-					if err != nil {
-						return nil, err
-					}
-					expandedValue = ev
-				} else {
-					return nil, fmt.Errorf("cannot filter on a non-primitive type")
-				}
-			} else {
-				return nil, fmt.Errorf("cannot filter on aggregate type with non-Schema element type")
-			}
+		filterValue, _ := rawFilterValue.(string)
+		if !isPrimitiveType(fieldSchema.Type) {
+			return nil, fmt.Errorf("cannot filter on a non-primitive type")
+		}
+		expandedValue, err := expandPrimitiveFilterValue(filterValue, fieldSchema.Type, matchBy)
+		if err != nil {
+			return nil, err
 		}
-
 		expandedFilterValues[i] = expandedValue
 	}
 
 	return expandedFilterValues, nil
 }
 
+// Returns true when any of given values of a record matches a filter value.
+func anyValueMatches(s *schema.Schema, values []interface{}, filterValue interface{}, matchBy string) bool {
+	for _, value := range values {
+		if valueMatches(s, value, filterValue, matchBy) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true when a record has any non-empty value at the filter attribute.
+func valuesExist(values []interface{}) bool {
+	for _, value := range values {
+		if s, ok := value.(string); !ok || s != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func applyFilters(recordSchema map[string]*schema.Schema, records []map[string]interface{}, filters []commonFilter) []map[string]interface{} {
 	for _, f := range filters {
 		// Handle multiple filters by applying them in order
 		var filteredRecords []map[string]interface{}
 
+		s, err := lookupAttributeSchema(recordSchema, f.attribute)
+		if err != nil {
+			// filters are validated on expansion, unknown attribute matches nothing
+			records = filteredRecords
+			continue
+		}
+		path := strings.Split(f.attribute, ".")
+
 		filterFunc := func(record map[string]interface{}) bool {
+			values := lookupAttributeValues(record, path)
+
+			switch f.matchBy {
+			case "exists":
+				return len(f.values) > 0 && valuesExist(values) == f.values[0].(bool)
+			case "not_in":
+				for _, filterValue := range f.values {
+					if anyValueMatches(s, values, filterValue, "in") {
+						return false
+					}
+				}
+				return true
+			}
+
 			result := f.all
 
 			for _, filterValue := range f.values {
-				thisValueMatches := valueMatches(recordSchema[f.attribute], record[f.attribute], filterValue, f.matchBy)
+				thisValueMatches := anyValueMatches(s, values, filterValue, f.matchBy)
 				if !f.all {
 					result = result || thisValueMatches
 				} else {
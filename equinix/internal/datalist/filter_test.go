@@ -328,3 +328,241 @@ func TestApplyFilters(t *testing.T) {
 		})
 	}
 }
+
+func devicesTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"hostname": {
+			Type: schema.TypeString,
+		},
+		"description": {
+			Type: schema.TypeString,
+		},
+		"ip_address": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address": {
+						Type: schema.TypeString,
+					},
+					"cidr": {
+						Type: schema.TypeInt,
+					},
+				},
+			},
+		},
+		"labels": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func devicesTestData() []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"hostname":    "web-1",
+			"description": "Frontend",
+			"ip_address": []interface{}{
+				map[string]interface{}{"address": "147.75.0.10", "cidr": 31},
+				map[string]interface{}{"address": "10.0.0.10", "cidr": 25},
+			},
+			"labels": map[string]interface{}{"env": "production"},
+		},
+		{
+			"hostname":    "web-2",
+			"description": "",
+			"ip_address": []interface{}{
+				map[string]interface{}{"address": "10.0.0.11", "cidr": 25},
+			},
+			"labels": map[string]interface{}{"env": "staging"},
+		},
+		{
+			"hostname":    "db-1",
+			"description": "DATABASE",
+			"ip_address":  nil,
+		},
+	}
+}
+
+func TestApplyFilters_nested(t *testing.T) {
+	testCases := []struct {
+		name         string
+		filter       commonFilter
+		expectations []string // Expectations are filled with the expected hostnames in order
+	}{
+		{
+			"ByNestedAttribute",
+			commonFilter{
+				"ip_address.address",
+				[]interface{}{"10.0.0.11", "147.75.0.10"},
+				false,
+				"in",
+			},
+			[]string{"web-1", "web-2"},
+		},
+		{
+			"ByNestedNumberAttribute",
+			commonFilter{
+				"ip_address.cidr",
+				[]interface{}{30},
+				false,
+				"greater_than",
+			},
+			[]string{"web-1"},
+		},
+		{
+			"ByMapKey",
+			commonFilter{
+				"labels.env",
+				[]interface{}{"production"},
+				false,
+				"in",
+			},
+			[]string{"web-1"},
+		},
+		{
+			"ByMapKeyNotIn",
+			commonFilter{
+				"labels.env",
+				[]interface{}{"production"},
+				false,
+				"not_in",
+			},
+			[]string{"web-2", "db-1"},
+		},
+		{
+			"ByMapKeyExists",
+			commonFilter{
+				"labels.env",
+				[]interface{}{true},
+				false,
+				"exists",
+			},
+			[]string{"web-1", "web-2"},
+		},
+		{
+			"ByEmptyValueNotExists",
+			commonFilter{
+				"description",
+				[]interface{}{false},
+				false,
+				"exists",
+			},
+			[]string{"web-2"},
+		},
+		{
+			"ByHostnameWithGlob",
+			commonFilter{
+				"hostname",
+				[]interface{}{regexp.MustCompile("^web-.$")},
+				false,
+				"glob",
+			},
+			[]string{"web-1", "web-2"},
+		},
+		{
+			"ByDescriptionWithRegularExpressionIgnoringCase",
+			commonFilter{
+				"description",
+				[]interface{}{regexp.MustCompile("(?i)^data")},
+				false,
+				"re_ignore_case",
+			},
+			[]string{"db-1"},
+		},
+		{
+			"ByUnknownAttribute",
+			commonFilter{
+				"ip_address.gateway",
+				[]interface{}{"10.0.0.1"},
+				false,
+				"in",
+			},
+			nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			devices := applyFilters(devicesTestSchema(), devicesTestData(), []commonFilter{testCase.filter})
+			var hostnames []string
+			for _, device := range devices {
+				hostnames = append(hostnames, device["hostname"].(string))
+			}
+			assert.Equal(t, testCase.expectations, hostnames)
+		})
+	}
+}
+
+func TestExpandFilters_nested(t *testing.T) {
+	// given
+	rawFilters := []interface{}{
+		map[string]interface{}{
+			"attribute": "hostname",
+			"values":    []interface{}{"web-*"},
+			"match_by":  "glob",
+		},
+		map[string]interface{}{
+			"attribute": "labels.env",
+			"values":    []interface{}{"true"},
+			"match_by":  "exists",
+		},
+		map[string]interface{}{
+			"attribute": "ip_address.cidr",
+			"values":    []interface{}{"25"},
+			"match_by":  "not_in",
+		},
+	}
+	// when
+	expandedFilters, err := expandFilters(devicesTestSchema(), rawFilters)
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, 3, len(expandedFilters), "All filters are expanded")
+	assert.True(t, expandedFilters[0].values[0].(*regexp.Regexp).MatchString("web-10"), "Glob matches any sequence")
+	assert.False(t, expandedFilters[0].values[0].(*regexp.Regexp).MatchString("db-web-1"), "Glob matches whole value")
+	assert.Equal(t, true, expandedFilters[1].values[0], "Exists value is parsed as bool")
+	assert.Equal(t, 25, expandedFilters[2].values[0], "Nested value is parsed with nested field type")
+}
+
+func TestExpandFilters_invalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rawFilter map[string]interface{}
+	}{
+		{
+			"ListOfObjects",
+			map[string]interface{}{"attribute": "ip_address", "values": []interface{}{"10.0.0.10"}},
+		},
+		{
+			"Map",
+			map[string]interface{}{"attribute": "labels", "values": []interface{}{"production"}},
+		},
+		{
+			"UnknownNestedAttribute",
+			map[string]interface{}{"attribute": "ip_address.gateway", "values": []interface{}{"10.0.0.1"}},
+		},
+		{
+			"PrimitiveWithNestedAttribute",
+			map[string]interface{}{"attribute": "hostname.value", "values": []interface{}{"web-1"}},
+		},
+		{
+			"GlobOnNumber",
+			map[string]interface{}{"attribute": "ip_address.cidr", "values": []interface{}{"2*"}, "match_by": "glob"},
+		},
+		{
+			"ExistsWithManyValues",
+			map[string]interface{}{"attribute": "hostname", "values": []interface{}{"true", "false"}, "match_by": "exists"},
+		},
+		{
+			"InvalidRegularExpression",
+			map[string]interface{}{"attribute": "hostname", "values": []interface{}{"web-("}, "match_by": "re_ignore_case"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := expandFilters(devicesTestSchema(), []interface{}{testCase.rawFilter})
+			assert.NotNil(t, err, "Error is returned")
+		})
+	}
+}
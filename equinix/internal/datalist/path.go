@@ -0,0 +1,113 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Resolves a dotted attribute path into the schema of primitive values at the
+// path. Paths go through attributes of objects nested in lists and sets, i.e.
+// `ip_address.address`, and through keys of maps, i.e. `tags.env`. Lists and
+// sets of primitive values resolve to the schema of their elements.
+func lookupAttributeSchema(recordSchema map[string]*schema.Schema, attribute string) (*schema.Schema, error) {
+	parts := strings.Split(attribute, ".")
+	fields := recordSchema
+	for i, part := range parts {
+		name := strings.Join(parts[:i+1], ".")
+		s, ok := fields[part]
+		if !ok {
+			return nil, fmt.Errorf("field '%s' does not exist in record schema", name)
+		}
+		last := i == len(parts)-1
+
+		switch s.Type {
+		case schema.TypeMap:
+			if last {
+				return nil, fmt.Errorf("field '%s' is a map, filter on its values with '%s.<key>'", name, name)
+			}
+			if i+2 != len(parts) {
+				return nil, fmt.Errorf("values of map '%s' have no nested fields", name)
+			}
+			if elem, ok := s.Elem.(*schema.Schema); ok && isPrimitiveType(elem.Type) {
+				return elem, nil
+			}
+			return &schema.Schema{Type: schema.TypeString}, nil
+
+		case schema.TypeList, schema.TypeSet:
+			switch elem := s.Elem.(type) {
+			case *schema.Resource:
+				if last {
+					return nil, fmt.Errorf("field '%s' is a list of objects, filter on their fields with '%s.<field>'", name, name)
+				}
+				fields = elem.Schema
+				continue
+			case *schema.Schema:
+				if !last {
+					return nil, fmt.Errorf("field '%s' has no nested fields", name)
+				}
+				if !isPrimitiveType(elem.Type) {
+					return nil, fmt.Errorf("cannot filter on a non-primitive type")
+				}
+				return elem, nil
+			}
+			return nil, fmt.Errorf("cannot filter on aggregate type with non-Schema element type")
+
+		default:
+			if !last {
+				return nil, fmt.Errorf("field '%s' has no nested fields", name)
+			}
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("field '%s' does not exist in record schema", attribute)
+}
+
+// Returns all primitive values of a record at a given attribute path. Values
+// of lists and sets, including lists of nested objects, are flattened. Missing
+// attributes and map keys have no values.
+func lookupAttributeValues(value interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		switch v := value.(type) {
+		case nil:
+			return nil
+		case *schema.Set:
+			return v.List()
+		case []interface{}:
+			return v
+		case []string:
+			values := make([]interface{}, len(v))
+			for i, s := range v {
+				values[i] = s
+			}
+			return values
+		}
+		return []interface{}{value}
+	}
+
+	var values []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if elem, ok := v[path[0]]; ok {
+			values = lookupAttributeValues(elem, path[1:])
+		}
+	case map[string]string:
+		if elem, ok := v[path[0]]; ok {
+			values = lookupAttributeValues(elem, path[1:])
+		}
+	case *schema.Set:
+		for _, elem := range v.List() {
+			values = append(values, lookupAttributeValues(elem, path)...)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			values = append(values, lookupAttributeValues(elem, path)...)
+		}
+	case []map[string]interface{}:
+		for _, elem := range v {
+			values = append(values, lookupAttributeValues(elem, path)...)
+		}
+	}
+	return values
+}
@@ -0,0 +1,86 @@
+package datalist
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// Filter is a parsed filter of a data list query, as passed to the
+// PushdownQuery function of a ResourceConfig.
+type Filter struct {
+	// Attribute is a dotted path of the filtered attribute in record schema.
+	Attribute string
+
+	// Values are filter values parsed into the Go type of the attribute:
+	// string, bool, int or float64. Values of `re`, `re_ignore_case` and
+	// `glob` filters are *regexp.Regexp and the value of `exists` is bool.
+	Values []interface{}
+
+	// All is true when a record has to match all values, otherwise it
+	// has to match any of them.
+	All bool
+
+	// MatchBy is the type of comparison, i.e. `in` or `substring`.
+	MatchBy string
+}
+
+// Sort is a parsed sort of a data list query, as passed to the
+// PushdownQuery function of a ResourceConfig.
+type Sort struct {
+	// Attribute is the name of the sorted attribute in record schema.
+	Attribute string
+
+	// Direction is either `asc` or `desc`. Empty direction is ascending.
+	Direction string
+}
+
+// Translates filters, sorts and limit of a query with the PushdownQuery
+// function of a given config. Returns parameters for GetRecords and the
+// filters that have to be applied to loaded records. Without PushdownQuery no
+// parameters are returned and all filters are applied to loaded records.
+func pushdownQuery(config *ResourceConfig, meta interface{}, extra map[string]interface{}, filters []commonFilter, sorts []commonSort, limit int) (map[string]interface{}, []commonFilter, error) {
+	if config.PushdownQuery == nil {
+		return nil, filters, nil
+	}
+
+	exportedFilters := make([]Filter, len(filters))
+	for i, f := range filters {
+		exportedFilters[i] = Filter{
+			Attribute: f.attribute,
+			Values:    f.values,
+			All:       f.all,
+			MatchBy:   f.matchBy,
+		}
+	}
+	exportedSorts := make([]Sort, len(sorts))
+	for i, s := range sorts {
+		exportedSorts[i] = Sort{
+			Attribute: s.attribute,
+			Direction: s.direction,
+		}
+	}
+
+	params, remainingFilters, err := config.PushdownQuery(meta, extra, exportedFilters, exportedSorts, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expandedFilters := make([]commonFilter, len(remainingFilters))
+	for i, f := range remainingFilters {
+		expandedFilters[i] = commonFilter{
+			attribute: f.Attribute,
+			values:    f.Values,
+			all:       f.All,
+			matchBy:   f.MatchBy,
+		}
+	}
+	return params, expandedFilters, nil
+}
+
+// Returns the limit of a query that can be pushed down. The number of
+// matching records is checked before the limit is applied, so the limit is
+// not pushed down when a single result is required. Any positive limit keeps
+// the check of required results.
+func pushdownLimit(d *schema.ResourceData) int {
+	if d.Get("require_single_result").(bool) {
+		return 0
+	}
+	return d.Get("limit").(int)
+}
@@ -0,0 +1,143 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The number of record IDs listed in diagnostics of result assertions.
+const resultDiagnosticRecords = 5
+
+// Returns schema of the arguments and computed attributes shaping results
+// of a data list resource.
+func resultSchema(config *ResourceConfig, recordSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	resultSchema := map[string]*schema.Schema{
+		"limit": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The maximum number of results to return, applied after filters and sorts",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"require_results": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "If is set to true, the read fails when no results match the filters",
+		},
+		"require_single_result": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "If is set to true, the read fails unless exactly one result matches the filters",
+		},
+		"group_by": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The attribute used to group results. Number of results per each value of the attribute is exported in `group_counts`",
+			ValidateFunc: validateFilterAttribute(recordSchema),
+		},
+		"group_counts": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "Number of results per each value of the `group_by` attribute",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		"result_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The number of results",
+		},
+	}
+	if hasRecordIDs(recordSchema) {
+		resultSchema["ids"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: fmt.Sprintf("IDs of %s in the order of results", config.ResultAttributeName),
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+	return resultSchema
+}
+
+// Returns true when records have string `id` attribute, exported in `ids`.
+func hasRecordIDs(recordSchema map[string]*schema.Schema) bool {
+	s, ok := recordSchema["id"]
+	return ok && s.Type == schema.TypeString
+}
+
+// Returns error diagnostics when a number of matched records does not satisfy
+// `require_results` and `require_single_result` arguments.
+func checkResultCount(config *ResourceConfig, d *schema.ResourceData, records []map[string]interface{}) diag.Diagnostics {
+	requireSingle := d.Get("require_single_result").(bool)
+	requireAny := d.Get("require_results").(bool)
+
+	switch {
+	case len(records) == 0 && (requireSingle || requireAny):
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("No %s match the query", config.ResultAttributeName),
+			Detail: fmt.Sprintf("The query requires at least one result, but none of the %s matches its filters. "+
+				"Check values of `filter` and query arguments, or disable `require_results` and `require_single_result` to allow empty results.",
+				config.ResultAttributeName),
+		}}
+	case len(records) > 1 && requireSingle:
+		detail := fmt.Sprintf("The query requires a single result, but %d of the %s match its filters. "+
+			"Add filters to select a single result, or disable `require_single_result`.",
+			len(records), config.ResultAttributeName)
+		if hasRecordIDs(config.RecordSchema) {
+			var ids []string
+			for i, record := range records {
+				if i == resultDiagnosticRecords {
+					ids = append(ids, "...")
+					break
+				}
+				ids = append(ids, fmt.Sprint(record["id"]))
+			}
+			detail += fmt.Sprintf(" Matching IDs: %s", strings.Join(ids, ", "))
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Multiple %s match the query", config.ResultAttributeName),
+			Detail:   detail,
+		}}
+	}
+	return nil
+}
+
+// Returns at most a given number of records. Limit lower than one means
+// no limit.
+func applyLimit(records []map[string]interface{}, limit int) []map[string]interface{} {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
+
+// Counts records per each value of a given attribute. Records with multiple
+// values of the attribute, i.e. of a list, are counted in each of their
+// groups, records without the attribute are not counted.
+func groupRecords(records []map[string]interface{}, attribute string) map[string]interface{} {
+	path := strings.Split(attribute, ".")
+	counts := map[string]int{}
+	for _, record := range records {
+		seen := map[string]bool{}
+		for _, value := range lookupAttributeValues(record, path) {
+			key := fmt.Sprint(value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			counts[key]++
+		}
+	}
+
+	groups := make(map[string]interface{}, len(counts))
+	for key, count := range counts {
+		groups[key] = count
+	}
+	return groups
+}
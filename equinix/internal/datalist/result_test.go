@@ -0,0 +1,120 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataListResourceRead_limit(t *testing.T) {
+	// given
+	config := testResourceConfig()
+	query := map[string]interface{}{
+		"sort":     []interface{}{map[string]interface{}{"attribute": "memory", "direction": "desc"}},
+		"limit":    2,
+		"group_by": "regions_set",
+	}
+	// when
+	d := readTestResource(t, config, query)
+	// then
+	sizes := d.Get("sizes").([]interface{})
+	assert.Equal(t, 2, len(sizes), "Number of results is limited")
+	assert.Equal(t, "s-4vcpu-8gb", sizes[0].(map[string]interface{})["slug"], "Limit is applied after sort")
+	assert.Equal(t, 2, d.Get("result_count"), "Count of limited results is set")
+	assert.Equal(t, map[string]interface{}{"ams1": 1, "ams2": 1, "nyc1": 1, "nyc2": 1}, d.Get("group_counts"), "Limited results are grouped")
+	_, hasIDs := d.GetOk("ids")
+	assert.False(t, hasIDs, "IDs are not set for records without id")
+}
+
+func TestDataListResourceRead_ids(t *testing.T) {
+	// given
+	config := testResourceConfig()
+	config.RecordSchema["id"] = &schema.Schema{Type: schema.TypeString}
+	flattenRecord := config.FlattenRecord
+	config.FlattenRecord = func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+		flattened, err := flattenRecord(record, meta, extra)
+		if err != nil {
+			return nil, err
+		}
+		flattened["id"] = "id-" + flattened["slug"].(string)
+		return flattened, nil
+	}
+	query := map[string]interface{}{
+		"sort": []interface{}{map[string]interface{}{"attribute": "slug"}},
+	}
+	// when
+	d := readTestResource(t, config, query)
+	// then
+	assert.Equal(t, []interface{}{"id-s-1vcpu-1gb", "id-s-2vcpu-2gb", "id-s-4vcpu-8gb"}, d.Get("ids"), "IDs are set in order of results")
+	assert.Equal(t, 3, d.Get("result_count"), "Count is set")
+}
+
+func TestDataListResourceRead_requireResults(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   map[string]interface{}
+		summary string
+	}{
+		{
+			"NoResults",
+			map[string]interface{}{
+				"filter":          []interface{}{map[string]interface{}{"attribute": "slug", "values": []interface{}{"missing"}}},
+				"require_results": true,
+			},
+			"No sizes match the query",
+		},
+		{
+			"NoSingleResult",
+			map[string]interface{}{
+				"filter":                []interface{}{map[string]interface{}{"attribute": "slug", "values": []interface{}{"missing"}}},
+				"require_single_result": true,
+			},
+			"No sizes match the query",
+		},
+		{
+			"MultipleResults",
+			map[string]interface{}{
+				"filter":                []interface{}{map[string]interface{}{"attribute": "vcpus", "values": []interface{}{"1", "2"}}},
+				"require_single_result": true,
+				"limit":                 1,
+			},
+			"Multiple sizes match the query",
+		},
+		{
+			"SingleResult",
+			map[string]interface{}{
+				"filter":                []interface{}{map[string]interface{}{"attribute": "vcpus", "values": []interface{}{"1"}}},
+				"require_single_result": true,
+				"require_results":       true,
+			},
+			"",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := NewResource(testResourceConfig())
+			d := schema.TestResourceDataRaw(t, r.Schema, testCase.query)
+			diags := r.ReadContext(context.Background(), d, nil)
+			if testCase.summary == "" {
+				assert.False(t, diags.HasError(), "Error is not returned")
+				return
+			}
+			assert.True(t, diags.HasError(), "Error is returned")
+			assert.Equal(t, testCase.summary, diags[0].Summary)
+		})
+	}
+}
+
+func TestGroupRecords(t *testing.T) {
+	// given
+	records := devicesTestData()
+	// when
+	byLabel := groupRecords(records, "labels.env")
+	byNetwork := groupRecords(records, "ip_address.cidr")
+	// then
+	assert.Equal(t, map[string]interface{}{"production": 1, "staging": 1}, byLabel, "Records without value are not counted")
+	assert.Equal(t, map[string]interface{}{"31": 1, "25": 2}, byNetwork, "Record is counted once per value")
+}
@@ -2,11 +2,13 @@ package datalist
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -33,6 +35,19 @@ type ResourceConfig struct {
 
 	// Extra parameters to expose on the datasource alongside `filter` and `sort`.
 	ExtraQuerySchema map[string]*schema.Schema
+
+	// Optional. Translates filters and sorts of a query into parameters of the
+	// API request made by GetRecords, such as API query parameters or search and
+	// paging of packngo.ListOptions. Returned parameters are added to the `extra`
+	// map passed to GetRecords and FlattenRecord, so their keys must not collide
+	// with ExtraQuerySchema. Returns the filters that were not translated and
+	// still have to be applied to loaded records. Filters the API evaluates only
+	// approximately, i.e. with a full-text search, should be returned as well.
+	// Sorts are always applied to loaded records. Limit is the maximum number
+	// of records in results, or 0 when all matching records are needed. It can
+	// be applied by GetRecords only when no filters are returned and there are
+	// no sorts, otherwise records beyond the limit may be the ones that match.
+	PushdownQuery func(meta interface{}, extra map[string]interface{}, filters []Filter, sorts []Sort, limit int) (map[string]interface{}, []Filter, error)
 }
 
 // Returns a new "data list" resource given the specified configuration. This
@@ -46,21 +61,12 @@ func NewResource(config *ResourceConfig) *schema.Resource {
 		log.Panicf("datalist.NewResource: invalid resource configuration: %v", err)
 	}
 
-	recordSchema := map[string]*schema.Schema{}
-	for attributeName, attributeSchema := range config.RecordSchema {
-		newAttributeSchema := &schema.Schema{}
-		*newAttributeSchema = *attributeSchema
-		newAttributeSchema.Computed = true
-		newAttributeSchema.Required = false
-		newAttributeSchema.Optional = false
-		recordSchema[attributeName] = newAttributeSchema
-	}
+	recordSchema := computedRecordSchema(config.RecordSchema)
 
-	filterAttributes := computeFilterAttributes(recordSchema)
 	sortAttributes := computeSortAttributes(recordSchema)
 
 	datasourceSchema := map[string]*schema.Schema{
-		"filter": filterSchema(filterAttributes),
+		"filter": filterSchema(recordSchema),
 		"sort":   sortSchema(sortAttributes),
 		config.ResultAttributeName: {
 			Type:        schema.TypeList,
@@ -72,6 +78,10 @@ func NewResource(config *ResourceConfig) *schema.Resource {
 		},
 	}
 
+	for attr, value := range resultSchema(config, recordSchema) {
+		datasourceSchema[attr] = value
+	}
+
 	for attr, value := range config.ExtraQuerySchema {
 		datasourceSchema[attr] = value
 	}
@@ -89,6 +99,28 @@ func dataListResourceRead(config *ResourceConfig) schema.ReadContextFunc {
 			extra[attr] = d.Get(attr)
 		}
 
+		var filters []commonFilter
+		if v, ok := d.GetOk("filter"); ok {
+			var err error
+			filters, err = expandFilters(config.RecordSchema, v.(*schema.Set).List())
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		var sorts []commonSort
+		if v, ok := d.GetOk("sort"); ok {
+			sorts = expandSorts(v.([]interface{}))
+		}
+
+		params, filters, err := pushdownQuery(config, meta, extra, filters, sorts, pushdownLimit(d))
+		if err != nil {
+			return diag.Errorf("Unable to translate query: %s", err)
+		}
+		for key, value := range params {
+			extra[key] = value
+		}
+
 		records, err := config.GetRecords(meta, extra)
 		if err != nil {
 			return diag.Errorf("Unable to load records: %s", err)
@@ -103,40 +135,152 @@ func dataListResourceRead(config *ResourceConfig) schema.ReadContextFunc {
 			flattenedRecords[i] = flattenedRecord
 		}
 
-		if v, ok := d.GetOk("filter"); ok {
-			filters, err := expandFilters(config.RecordSchema, v.(*schema.Set).List())
-			if err != nil {
-				return diag.FromErr(err)
-			}
+		if len(filters) > 0 {
 			flattenedRecords = applyFilters(config.RecordSchema, flattenedRecords, filters)
 		}
 
-		if v, ok := d.GetOk("sort"); ok {
-			sorts := expandSorts(v.([]interface{}))
+		if len(sorts) > 0 {
 			flattenedRecords = applySorts(config.RecordSchema, flattenedRecords, sorts)
 		}
 
-		d.SetId(resource.UniqueId())
+		if diags := checkResultCount(config, d, flattenedRecords); diags.HasError() {
+			return diags
+		}
+
+		flattenedRecords = applyLimit(flattenedRecords, d.Get("limit").(int))
+
+		id, err := dataListResourceID(config, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(id)
 
 		if err := d.Set(config.ResultAttributeName, flattenedRecords); err != nil {
 			return diag.Errorf("unable to set `%s` attribute: %s", config.ResultAttributeName, err)
 		}
 
+		if err := d.Set("result_count", len(flattenedRecords)); err != nil {
+			return diag.Errorf("unable to set `result_count` attribute: %s", err)
+		}
+
+		if hasRecordIDs(config.RecordSchema) {
+			ids := make([]string, len(flattenedRecords))
+			for i, record := range flattenedRecords {
+				ids[i], _ = record["id"].(string)
+			}
+			if err := d.Set("ids", ids); err != nil {
+				return diag.Errorf("unable to set `ids` attribute: %s", err)
+			}
+		}
+
+		var groups map[string]interface{}
+		if v, ok := d.GetOk("group_by"); ok {
+			groups = groupRecords(flattenedRecords, v.(string))
+		}
+		if err := d.Set("group_counts", groups); err != nil {
+			return diag.Errorf("unable to set `group_counts` attribute: %s", err)
+		}
+
 		return nil
 	}
 }
 
-// Compute the set of filter attributes for the resource.
-func computeFilterAttributes(recordSchema map[string]*schema.Schema) []string {
-	var filterAttributes []string
+// Computes ID of a data list resource out of its query arguments: filters,
+// sorts, arguments shaping results and extra query parameters. The same
+// query always has the same ID, so refreshing the data source does not cause
+// changes of dependent resources.
+func dataListResourceID(config *ResourceConfig, d *schema.ResourceData) (string, error) {
+	queryAttributes := []string{"filter", "sort", "limit", "require_results", "require_single_result", "group_by"}
+	extraAttributes := make([]string, 0, len(config.ExtraQuerySchema))
+	for attr := range config.ExtraQuerySchema {
+		extraAttributes = append(extraAttributes, attr)
+	}
+	sort.Strings(extraAttributes)
+	queryAttributes = append(queryAttributes, extraAttributes...)
 
-	for attr, schemaForAttr := range recordSchema {
-		if schemaForAttr.Type != schema.TypeMap {
-			filterAttributes = append(filterAttributes, attr)
+	query := make([]interface{}, 0, 2*len(queryAttributes)+1)
+	query = append(query, config.ResultAttributeName)
+	for _, attr := range queryAttributes {
+		value, err := canonicalQueryValue(d.Get(attr))
+		if err != nil {
+			return "", fmt.Errorf("unable to compute ID out of `%s` attribute: %s", attr, err)
 		}
+		query = append(query, attr, value)
 	}
 
-	return filterAttributes
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return "", fmt.Errorf("unable to compute ID: %s", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(encoded)), nil
+}
+
+// Converts a value of query attribute into a value with stable JSON encoding.
+// Sets are converted into lists of encoded elements in sorted order, maps are
+// encoded with sorted keys.
+func canonicalQueryValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *schema.Set:
+		encoded := make([]string, v.Len())
+		for i, elem := range v.List() {
+			c, err := canonicalQueryValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			e, err := json.Marshal(c)
+			if err != nil {
+				return nil, err
+			}
+			encoded[i] = string(e)
+		}
+		sort.Strings(encoded)
+		canonical := make([]json.RawMessage, len(encoded))
+		for i, e := range encoded {
+			canonical[i] = json.RawMessage(e)
+		}
+		return canonical, nil
+	case []interface{}:
+		canonical := make([]interface{}, len(v))
+		for i, elem := range v {
+			c, err := canonicalQueryValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			canonical[i] = c
+		}
+		return canonical, nil
+	case map[string]interface{}:
+		canonical := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			c, err := canonicalQueryValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			canonical[key] = c
+		}
+		return canonical, nil
+	}
+	return value, nil
+}
+
+// Returns a copy of record schema with all attributes, including attributes
+// of nested objects, marked as computed.
+func computedRecordSchema(recordSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	computedSchema := map[string]*schema.Schema{}
+	for attributeName, attributeSchema := range recordSchema {
+		newAttributeSchema := &schema.Schema{}
+		*newAttributeSchema = *attributeSchema
+		newAttributeSchema.Computed = true
+		newAttributeSchema.Required = false
+		newAttributeSchema.Optional = false
+		if elem, ok := attributeSchema.Elem.(*schema.Resource); ok {
+			newAttributeSchema.Elem = &schema.Resource{
+				Schema: computedRecordSchema(elem.Schema),
+			}
+		}
+		computedSchema[attributeName] = newAttributeSchema
+	}
+	return computedSchema
 }
 
 // Compute the set of sort attributes for the source.
@@ -165,5 +309,16 @@ func validateResourceConfig(config *ResourceConfig) error {
 		return fmt.Errorf("ResultAttributeName must be specified")
 	}
 
+	// Ensure that extra query parameters do not override common attributes.
+	reserved := resultSchema(config, config.RecordSchema)
+	reserved["filter"] = nil
+	reserved["sort"] = nil
+	reserved[config.ResultAttributeName] = nil
+	for attr := range config.ExtraQuerySchema {
+		if _, ok := reserved[attr]; ok {
+			return fmt.Errorf("ExtraQuerySchema must not contain reserved attribute %q", attr)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,176 @@
+package datalist
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResourceConfig() *ResourceConfig {
+	return &ResourceConfig{
+		RecordSchema:        sizesTestSchema(),
+		ResultAttributeName: "sizes",
+		FlattenRecord: func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+			return record.(map[string]interface{}), nil
+		},
+		GetRecords: func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+			records := make([]interface{}, 0)
+			for _, record := range sizesTestDataForSorts() {
+				records = append(records, record)
+			}
+			return records, nil
+		},
+		ExtraQuerySchema: map[string]*schema.Schema{
+			"project_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func readTestResource(t *testing.T, config *ResourceConfig, raw map[string]interface{}) *schema.ResourceData {
+	r := NewResource(config)
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if diags := r.ReadContext(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("read returned error: %v", diags)
+	}
+	return d
+}
+
+func TestDataListResourceID(t *testing.T) {
+	// given
+	config := testResourceConfig()
+	query := map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "slug", "values": []interface{}{"s-1vcpu-1gb"}},
+			map[string]interface{}{"attribute": "vcpus", "values": []interface{}{"1"}},
+		},
+		"sort":       []interface{}{map[string]interface{}{"attribute": "memory", "direction": "desc"}},
+		"project_id": "project",
+	}
+	reorderedQuery := map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "vcpus", "values": []interface{}{"1"}},
+			map[string]interface{}{"attribute": "slug", "values": []interface{}{"s-1vcpu-1gb"}},
+		},
+		"sort":       []interface{}{map[string]interface{}{"attribute": "memory", "direction": "desc"}},
+		"project_id": "project",
+	}
+	otherQuery := map[string]interface{}{
+		"filter":     query["filter"],
+		"sort":       query["sort"],
+		"project_id": "otherProject",
+	}
+	limitedQuery := map[string]interface{}{
+		"filter":     query["filter"],
+		"sort":       query["sort"],
+		"project_id": "project",
+		"limit":      1,
+	}
+	// when
+	first := readTestResource(t, config, query).Id()
+	second := readTestResource(t, config, query).Id()
+	reordered := readTestResource(t, config, reorderedQuery).Id()
+	other := readTestResource(t, config, otherQuery).Id()
+	limited := readTestResource(t, config, limitedQuery).Id()
+	// then
+	assert.NotEmpty(t, first, "ID is set")
+	assert.Equal(t, first, second, "The same query has the same ID")
+	assert.Equal(t, first, reordered, "Order of filters does not change ID")
+	assert.NotEqual(t, first, other, "Different query has different ID")
+	assert.NotEqual(t, first, limited, "Limited query has different ID")
+}
+
+func TestDataListResourceRead_pushdownQuery(t *testing.T) {
+	// given
+	config := testResourceConfig()
+	var pushedFilters []Filter
+	var pushedSorts []Sort
+	var loadExtra map[string]interface{}
+	config.PushdownQuery = func(meta interface{}, extra map[string]interface{}, filters []Filter, sorts []Sort, limit int) (map[string]interface{}, []Filter, error) {
+		pushedFilters, pushedSorts = filters, sorts
+		var remaining []Filter
+		params := map[string]interface{}{}
+		for _, f := range filters {
+			if f.Attribute == "vcpus" && f.MatchBy == "in" {
+				params["vcpus"] = f.Values
+				continue
+			}
+			remaining = append(remaining, f)
+		}
+		return params, remaining, nil
+	}
+	getRecords := config.GetRecords
+	config.GetRecords = func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+		loadExtra = extra
+		records, err := getRecords(meta, extra)
+		if err != nil {
+			return nil, err
+		}
+		var selected []interface{}
+		for _, record := range records {
+			for _, vcpus := range extra["vcpus"].([]interface{}) {
+				if record.(map[string]interface{})["vcpus"] == vcpus {
+					selected = append(selected, record)
+				}
+			}
+		}
+		return selected, nil
+	}
+	query := map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "vcpus", "values": []interface{}{"1", "4"}},
+			map[string]interface{}{"attribute": "memory", "values": []interface{}{"8192"}},
+		},
+		"sort":       []interface{}{map[string]interface{}{"attribute": "slug", "direction": "desc"}},
+		"project_id": "project",
+	}
+	// when
+	d := readTestResource(t, config, query)
+	// then
+	assert.Equal(t, 2, len(pushedFilters), "All filters are passed to pushdown")
+	assert.Equal(t, []Sort{{Attribute: "slug", Direction: "desc"}}, pushedSorts, "Sorts are passed to pushdown")
+	assert.Equal(t, []interface{}{1, 4}, loadExtra["vcpus"], "Pushdown parameters are passed to GetRecords")
+	assert.Equal(t, "project", loadExtra["project_id"], "Extra query parameters are kept")
+	sizes := d.Get("sizes").([]interface{})
+	assert.Equal(t, 1, len(sizes), "Remaining filters are applied to loaded records")
+	assert.Equal(t, "s-4vcpu-8gb", sizes[0].(map[string]interface{})["slug"], "Record matching all filters is returned")
+}
+
+func TestDataListResourceRead_pushdownLimit(t *testing.T) {
+	// given
+	config := testResourceConfig()
+	var pushedLimits []int
+	config.PushdownQuery = func(meta interface{}, extra map[string]interface{}, filters []Filter, sorts []Sort, limit int) (map[string]interface{}, []Filter, error) {
+		pushedLimits = append(pushedLimits, limit)
+		return nil, filters, nil
+	}
+	// when
+	readTestResource(t, config, map[string]interface{}{"limit": 2})
+	readTestResource(t, config, map[string]interface{}{"limit": 2, "require_results": true})
+	readTestResource(t, config, map[string]interface{}{"limit": 1, "require_single_result": true,
+		"filter": []interface{}{map[string]interface{}{"attribute": "slug", "values": []interface{}{"s-1vcpu-1gb"}}},
+	})
+	readTestResource(t, config, map[string]interface{}{})
+	// then
+	assert.Equal(t, []int{2, 2, 0, 0}, pushedLimits, "Limit is not pushed down when a single result is required")
+}
+
+func TestDataListResourceRead_pushdownQueryError(t *testing.T) {
+	// given
+	config := testResourceConfig()
+	config.PushdownQuery = func(meta interface{}, extra map[string]interface{}, filters []Filter, sorts []Sort, limit int) (map[string]interface{}, []Filter, error) {
+		return nil, nil, fmt.Errorf("unsupported query")
+	}
+	r := NewResource(config)
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	// when
+	diags := r.ReadContext(context.Background(), d, nil)
+	// then
+	assert.True(t, diags.HasError(), "Error is returned")
+	assert.Contains(t, diags[0].Summary, "unsupported query", "Error of pushdown is returned")
+}
@@ -12,23 +12,64 @@ func floatApproxEquals(a, b float64) bool {
 	return math.Abs(a-b) < 0.000001
 }
 
+// Converts an integer value of a record to int. Records may carry any of Go
+// integer types, depending on how they were flattened.
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// Converts a floating point value of a record to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
 func valueMatches(s *schema.Schema, value interface{}, filterValue interface{}, matchBy string) bool {
 	switch s.Type {
 	case schema.TypeString:
+		val, ok := value.(string)
+		if !ok {
+			return false
+		}
 		switch matchBy {
 		case "substring":
-			return strings.Contains(value.(string), filterValue.(string))
-		case "re":
-			return filterValue.(*regexp.Regexp).MatchString(value.(string))
+			filter, ok := filterValue.(string)
+			return ok && strings.Contains(val, filter)
+		case "re", "re_ignore_case", "glob":
+			filter, ok := filterValue.(*regexp.Regexp)
+			return ok && filter.MatchString(val)
 		}
-		return strings.EqualFold(filterValue.(string), value.(string))
+		filter, ok := filterValue.(string)
+		return ok && strings.EqualFold(filter, val)
 
 	case schema.TypeBool:
-		return filterValue.(bool) == value.(bool)
+		val, ok := value.(bool)
+		filter, filterOk := filterValue.(bool)
+		return ok && filterOk && filter == val
 
 	case schema.TypeInt:
-		val := value.(int)
-		filter := filterValue.(int)
+		val, ok := toInt(value)
+		filter, filterOk := filterValue.(int)
+		if !ok || !filterOk {
+			return false
+		}
 		switch matchBy {
 		case "less_than":
 			return val < filter
@@ -42,8 +83,11 @@ func valueMatches(s *schema.Schema, value interface{}, filterValue interface{},
 		return val == filter
 
 	case schema.TypeFloat:
-		val := value.(float64)
-		filter := filterValue.(float64)
+		val, ok := toFloat(value)
+		filter, filterOk := filterValue.(float64)
+		if !ok || !filterOk {
+			return false
+		}
 		switch matchBy {
 		case "less_than":
 			return val != 0. && (val < filter)
@@ -55,25 +99,6 @@ func valueMatches(s *schema.Schema, value interface{}, filterValue interface{},
 			return val != 0. && ((val > filter) || floatApproxEquals(filter, val))
 		}
 		return floatApproxEquals(filter, val)
-
-	case schema.TypeList:
-		listValues := value.([]interface{})
-		result := false
-		for _, listValue := range listValues {
-			valueDoesMatch := valueMatches(s.Elem.(*schema.Schema), listValue, filterValue, matchBy)
-			result = result || valueDoesMatch
-		}
-		return result
-
-	case schema.TypeSet:
-		setValue := value.(*schema.Set)
-		listValues := setValue.List()
-		result := false
-		for _, listValue := range listValues {
-			valueDoesMatch := valueMatches(s.Elem.(*schema.Schema), listValue, filterValue, matchBy)
-			result = result || valueDoesMatch
-		}
-		return result
 	}
 
 	return false
@@ -0,0 +1,176 @@
+package fakeapi
+
+import (
+	"strconv"
+)
+
+// Collections of Equinix Metal API
+var (
+	MetalProjects = &Collection{
+		CreatePath: "/metal/v1/projects",
+		ItemPath:   "/metal/v1/projects",
+		IDField:    "id",
+		ListKey:    "projects",
+	}
+
+	MetalDevices = &Collection{
+		CreatePath:  "/metal/v1/projects/{project}/devices",
+		ItemPath:    "/metal/v1/devices",
+		IDField:     "id",
+		StatusField: "state",
+		Statuses:    []string{"queued", "provisioning", "active"},
+		Parents:     map[string]string{"project": "project"},
+		ListKey:     "devices",
+		Prepare:     prepareMetalDevice,
+	}
+
+	MetalVirtualNetworks = &Collection{
+		CreatePath: "/metal/v1/projects/{project}/virtual-networks",
+		ItemPath:   "/metal/v1/virtual-networks",
+		IDField:    "id",
+		Parents:    map[string]string{"project": "assigned_to"},
+		Rename: map[string]string{
+			"facility": "facility_code",
+			"metro":    "metro_code",
+		},
+		ListKey: "virtual_networks",
+		Prepare: prepareMetalVirtualNetwork,
+	}
+)
+
+// Collections of Equinix Fabric API
+var (
+	FabricL2Connections = &Collection{
+		CreatePath:     "/ecx/v3/l2/connections",
+		ItemPath:       "/ecx/v3/l2/connections",
+		IDField:        "uuid",
+		StatusField:    "status",
+		Statuses:       []string{"PROVISIONING", "PROVISIONED"},
+		DeleteStatuses: []string{"DEPROVISIONING", "DEPROVISIONED"},
+		Rename:         fabricL2ConnectionAttributes,
+		Prepare:        prepareFabricL2Connection,
+		CreateResponse: createFabricL2ConnectionResponse,
+	}
+
+	NetworkL2Connections = &Collection{
+		CreatePath:     "/ne/v1/l2/connections",
+		ItemPath:       "/ecx/v3/l2/connections",
+		IDField:        "uuid",
+		StatusField:    "status",
+		Statuses:       []string{"PROVISIONING", "PROVISIONED"},
+		DeleteStatuses: []string{"DEPROVISIONING", "DEPROVISIONED"},
+		Rename:         fabricL2ConnectionAttributes,
+		Prepare:        prepareFabricL2Connection,
+		CreateResponse: createFabricL2ConnectionResponse,
+	}
+)
+
+// Collections of Network Edge API
+var (
+	NetworkDevices = &Collection{
+		CreatePath:     "/ne/v1/devices",
+		ItemPath:       "/ne/v1/devices",
+		IDField:        "uuid",
+		StatusField:    "status",
+		Statuses:       []string{"INITIALIZING", "PROVISIONING", "PROVISIONED"},
+		DeleteStatuses: []string{"DEPROVISIONING", "DEPROVISIONED"},
+		Rename: map[string]string{
+			"virtualDeviceName": "name",
+			"hostNamePrefix":    "hostName",
+		},
+		Prepare: prepareNetworkDevice,
+		CreateResponse: func(obj map[string]interface{}) interface{} {
+			return map[string]interface{}{"uuid": obj["uuid"]}
+		},
+	}
+	NetworkBGPConfigurations = &Collection{
+		CreatePath:  "/ne/v1/bgp",
+		ItemPath:    "/ne/v1/bgp",
+		IDField:     "uuid",
+		StatusField: "provisioningStatus",
+		Statuses:    []string{"PROVISIONING", "PROVISIONED"},
+		CreateResponse: func(obj map[string]interface{}) interface{} {
+			return map[string]interface{}{"uuid": obj["uuid"]}
+		},
+	}
+)
+
+// DefaultCollections returns all collections supported by the fake server
+func DefaultCollections() []*Collection {
+	return []*Collection{
+		MetalProjects,
+		MetalDevices,
+		MetalVirtualNetworks,
+		FabricL2Connections,
+		NetworkL2Connections,
+		NetworkDevices,
+		NetworkBGPConfigurations,
+	}
+}
+
+var fabricL2ConnectionAttributes = map[string]string{
+	"primaryName":          "name",
+	"connectionNewName":    "name",
+	"profileUUID":          "sellerServiceUUID",
+	"primaryPortUUID":      "portUUID",
+	"primaryVlanSTag":      "vlanSTag",
+	"primaryVlanCTag":      "vlanCTag",
+	"primaryZSidePortUUID": "zSidePortUUID",
+	"primaryZSideVlanSTag": "zSideVlanSTag",
+	"primaryZSideVlanCTag": "zSideVlanCTag",
+}
+
+func prepareMetalDevice(obj map[string]interface{}, _ map[string]string) {
+	for _, attr := range []string{"plan", "operating_system"} {
+		if v, ok := obj[attr].(string); ok {
+			obj[attr] = map[string]interface{}{"slug": v}
+		}
+	}
+	metro, _ := obj["metro"].(string)
+	if metro != "" {
+		obj["metro"] = map[string]interface{}{"code": metro}
+	}
+	if v, ok := obj["facility"].([]interface{}); ok && len(v) > 0 {
+		obj["facility"] = map[string]interface{}{"code": v[0]}
+	} else if metro != "" {
+		// devices created in a metro are deployed to one of its facilities
+		obj["facility"] = map[string]interface{}{"code": metro + "1"}
+	} else {
+		delete(obj, "facility")
+	}
+	obj["network_ports"] = []interface{}{}
+	obj["ip_addresses"] = []interface{}{}
+}
+
+func prepareMetalVirtualNetwork(obj map[string]interface{}, _ map[string]string) {
+	if vxlan, ok := obj["vxlan"].(float64); !ok || vxlan == 0 {
+		obj["vxlan"] = 1000
+	}
+	delete(obj, "project_id")
+}
+
+func prepareFabricL2Connection(obj map[string]interface{}, _ map[string]string) {
+	obj["providerStatus"] = "AVAILABLE"
+	obj["redundancyType"] = "primary"
+}
+
+func createFabricL2ConnectionResponse(obj map[string]interface{}) interface{} {
+	return map[string]interface{}{
+		"message":             "Connection Saved Successfully",
+		"primaryConnectionId": obj["uuid"],
+		"status":              "SUCCESS",
+	}
+}
+
+func prepareNetworkDevice(obj map[string]interface{}, _ map[string]string) {
+	obj["licenseStatus"] = "REGISTERED"
+	if v, ok := obj["termLength"].(string); ok {
+		if termLength, err := strconv.Atoi(v); err == nil {
+			obj["termLength"] = termLength
+		}
+	}
+	if v, ok := obj["core"].(float64); ok {
+		obj["core"] = map[string]interface{}{"core": v}
+	}
+	obj["redundancyType"] = "PRIMARY"
+}
@@ -0,0 +1,384 @@
+// Package fakeapi provides stateful, in-memory fake of Equinix Metal, Fabric
+// and Network Edge APIs. It is meant to be used in unit tests that exercise
+// full resource lifecycles without reaching real infrastructure.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collection describes single API collection served by the fake server
+type Collection struct {
+	// Path pattern of create and list requests. Segments in curly braces are
+	// treated as parameters, i.e. /metal/v1/projects/{project}/devices
+	CreatePath string
+
+	// Path prefix under which created objects are available, i.e. /metal/v1/devices
+	ItemPath string
+
+	// Name of the attribute holding object identifier
+	IDField string
+
+	// Name of the attribute holding object status
+	StatusField string
+
+	// Statuses that object goes through after creation. Object is created in
+	// the first status and moves to the next one on every read, including
+	// lists.
+	Statuses []string
+
+	// Statuses that object goes through after delete request. Object is
+	// removed immediately when there are no delete statuses.
+	DeleteStatuses []string
+
+	// Attributes referencing parent objects, keyed by path parameter names.
+	// Created objects get reference to parent, i.e. "project": {"id": "..."}
+	// and lists are filtered by them.
+	Parents map[string]string
+
+	// Request attributes that are renamed before object is stored or
+	// updated, i.e. "primaryName" to "name"
+	Rename map[string]string
+
+	// Name of the attribute wrapping list responses. Such lists are ordered
+	// by object path and paginated by page and per_page query parameters, as
+	// Metal lists are. Lists are returned in paginated, Fabric and Network
+	// Edge format when empty.
+	ListKey string
+
+	// Prepare modifies created object before it is stored, i.e. to map
+	// request attributes to response attributes. Path parameters of create
+	// request are provided as params.
+	Prepare func(obj map[string]interface{}, params map[string]string)
+
+	// CreateResponse builds create response body from stored object.
+	// Stored object is returned when not set.
+	CreateResponse func(obj map[string]interface{}) interface{}
+}
+
+// Request is a record of a request received by the fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   map[string]interface{}
+}
+
+type object struct {
+	collection *Collection
+	data       map[string]interface{}
+	statuses   []string
+	deleted    bool
+}
+
+// advanceStatus moves object to the next status, if any
+func (o *object) advanceStatus() {
+	if len(o.statuses) > 0 {
+		o.data[o.collection.StatusField] = o.statuses[0]
+		o.statuses = o.statuses[1:]
+	}
+}
+
+// Server is a httptest based, stateful fake of Equinix APIs
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	collections []*Collection
+	objects     map[string]*object
+	requests    []Request
+	sequence    int
+}
+
+// NewServer starts fake server serving given collections. Server with
+// DefaultCollections is started when no collections are given.
+// Caller should call Close when finished.
+func NewServer(collections ...*Collection) *Server {
+	if len(collections) == 0 {
+		collections = DefaultCollections()
+	}
+	s := &Server{
+		collections: collections,
+		objects:     make(map[string]*object),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Seed stores given object in a collection, bypassing status transitions.
+// Identifier of seeded object is returned.
+func (s *Server) Seed(c *Collection, data map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := data[c.IDField].(string)
+	if !ok || id == "" {
+		id = s.nextID()
+		data[c.IDField] = id
+	}
+	data["href"] = c.ItemPath + "/" + id
+	s.objects[c.ItemPath+"/"+id] = &object{collection: c, data: data}
+	return id
+}
+
+// Object returns copy of stored object available under given path
+func (s *Server) Object(path string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[path]
+	if !ok {
+		return nil, false
+	}
+	return copyMap(obj.data), true
+}
+
+// Requests returns all requests received by the server so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body map[string]interface{}
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.Query(), Body: body})
+
+	if obj, ok := s.objects[path]; ok {
+		s.handleItem(w, r, path, obj, body)
+		return
+	}
+	for _, c := range s.collections {
+		params, ok := matchPath(c.CreatePath, path)
+		if !ok {
+			continue
+		}
+		switch r.Method {
+		case http.MethodPost:
+			s.handleCreate(w, c, params, body)
+		case http.MethodGet:
+			s.handleList(w, r, c, params)
+		default:
+			writeError(w, path, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	writeError(w, path, http.StatusNotFound, "Not found")
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, c *Collection, params map[string]string, body map[string]interface{}) {
+	if body == nil {
+		body = make(map[string]interface{})
+	}
+	id := s.nextID()
+	body[c.IDField] = id
+	body["href"] = c.ItemPath + "/" + id
+	renameAttributes(body, c.Rename)
+	for param, attr := range c.Parents {
+		body[attr] = map[string]interface{}{"id": params[param]}
+	}
+	obj := &object{collection: c, data: body}
+	if len(c.Statuses) > 0 {
+		obj.statuses = c.Statuses[1:]
+		body[c.StatusField] = c.Statuses[0]
+	}
+	if c.Prepare != nil {
+		c.Prepare(body, params)
+	}
+	s.objects[c.ItemPath+"/"+id] = obj
+	var resp interface{} = body
+	if c.CreateResponse != nil {
+		resp = c.CreateResponse(copyMap(body))
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request, c *Collection, params map[string]string) {
+	query := r.URL.Query()
+	var paths []string
+	for path, obj := range s.objects {
+		if obj.collection != c || obj.deleted {
+			continue
+		}
+		if !matchParents(obj.data, c.Parents, params) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if c.ListKey != "" {
+		page, perPage, lastPage := 1, len(paths), 1
+		if n, err := strconv.Atoi(query.Get("per_page")); err == nil && n > 0 {
+			perPage = n
+			lastPage = (len(paths) + n - 1) / n
+		}
+		if n, err := strconv.Atoi(query.Get("page")); err == nil && n > 0 {
+			page = n
+		}
+		start := (page - 1) * perPage
+		if start > len(paths) {
+			start = len(paths)
+		}
+		end := start + perPage
+		if end > len(paths) {
+			end = len(paths)
+		}
+		meta := map[string]interface{}{"total": len(paths), "current_page": page, "last_page": lastPage}
+		if page < lastPage {
+			next := url.Values{}
+			for k, v := range query {
+				next[k] = v
+			}
+			next.Set("page", strconv.Itoa(page+1))
+			meta["next"] = map[string]interface{}{"href": r.URL.Path + "?" + next.Encode()}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			c.ListKey: s.listItems(paths[start:end]),
+			"meta":    meta,
+		})
+		return
+	}
+	items := s.listItems(paths)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content":     items,
+		"totalCount":  len(items),
+		"pageNumber":  0,
+		"isFirstPage": true,
+		"isLastPage":  true,
+	})
+}
+
+// listItems returns copies of objects under given paths and moves listed
+// objects to their next status
+func (s *Server) listItems(paths []string) []interface{} {
+	items := make([]interface{}, 0, len(paths))
+	for _, path := range paths {
+		obj := s.objects[path]
+		items = append(items, copyMap(obj.data))
+		obj.advanceStatus()
+	}
+	return items
+}
+
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request, path string, obj *object, body map[string]interface{}) {
+	c := obj.collection
+	switch r.Method {
+	case http.MethodGet:
+		if obj.deleted && len(obj.statuses) == 0 {
+			delete(s.objects, path)
+		}
+		writeJSON(w, http.StatusOK, copyMap(obj.data))
+		obj.advanceStatus()
+	case http.MethodPut, http.MethodPatch:
+		renameAttributes(body, c.Rename)
+		for k, v := range body {
+			obj.data[k] = v
+		}
+		writeJSON(w, http.StatusOK, copyMap(obj.data))
+	case http.MethodDelete:
+		if obj.deleted {
+			writeError(w, path, http.StatusNotFound, "Not found")
+			return
+		}
+		if len(c.DeleteStatuses) == 0 {
+			delete(s.objects, path)
+		} else {
+			obj.deleted = true
+			obj.data[c.StatusField] = c.DeleteStatuses[0]
+			obj.statuses = c.DeleteStatuses[1:]
+		}
+		if isMetalPath(path) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeError(w, path, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) nextID() string {
+	s.sequence++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.sequence, s.sequence)
+}
+
+func matchPath(pattern, path string) (map[string]string, bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i := range patternSegments {
+		if strings.HasPrefix(patternSegments[i], "{") && strings.HasSuffix(patternSegments[i], "}") {
+			params[strings.Trim(patternSegments[i], "{}")] = pathSegments[i]
+			continue
+		}
+		if patternSegments[i] != pathSegments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// matchParents checks if object belongs to parents given by path parameters
+func matchParents(data map[string]interface{}, parents map[string]string, params map[string]string) bool {
+	for param, attr := range parents {
+		ref, ok := data[attr].(map[string]interface{})
+		if !ok || ref["id"] != params[param] {
+			return false
+		}
+	}
+	return true
+}
+
+func renameAttributes(data map[string]interface{}, names map[string]string) {
+	for from, to := range names {
+		if v, ok := data[from]; ok {
+			delete(data, from)
+			data[to] = v
+		}
+	}
+}
+
+func isMetalPath(path string) bool {
+	return strings.HasPrefix(path, "/metal/")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%d", status))
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes error in a format of a service that given path belongs to
+func writeError(w http.ResponseWriter, path string, status int, msg string) {
+	if isMetalPath(path) {
+		writeJSON(w, status, map[string]interface{}{"errors": []string{msg}})
+		return
+	}
+	writeJSON(w, status, []map[string]interface{}{
+		{"errorCode": fmt.Sprintf("FAKE-%d", status), "errorMessage": msg},
+	})
+}
+
+func copyMap(source map[string]interface{}) map[string]interface{} {
+	target := make(map[string]interface{}, len(source))
+	for k, v := range source {
+		target[k] = v
+	}
+	return target
+}
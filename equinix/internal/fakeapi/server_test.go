@@ -0,0 +1,114 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func doRequest(t *testing.T, method, url string, body interface{}) (int, map[string]interface{}) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatalf("cannot encode request body: %s", err)
+		}
+	}
+	req, _ := http.NewRequest(method, url, &reqBody)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	defer resp.Body.Close()
+	var respBody map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&respBody)
+	return resp.StatusCode, respBody
+}
+
+func TestServer_statusTransitions(t *testing.T) {
+	// given
+	s := NewServer()
+	defer s.Close()
+	// when
+	code, created := doRequest(t, http.MethodPost, s.URL+"/ne/v1/devices", map[string]interface{}{
+		"virtualDeviceName": "test",
+		"termLength":        "12",
+	})
+	// then
+	assert.Equal(t, http.StatusCreated, code)
+	uuid := created["uuid"].(string)
+	statuses := make([]interface{}, 0)
+	for i := 0; i < 4; i++ {
+		_, device := doRequest(t, http.MethodGet, s.URL+"/ne/v1/devices/"+uuid, nil)
+		statuses = append(statuses, device["status"])
+		assert.Equal(t, "test", device["name"], "Request attribute is renamed")
+		assert.Equal(t, float64(12), device["termLength"], "Request attribute is converted")
+	}
+	assert.Equal(t, []interface{}{"INITIALIZING", "PROVISIONING", "PROVISIONED", "PROVISIONED"}, statuses)
+
+	// when
+	code, _ = doRequest(t, http.MethodDelete, s.URL+"/ne/v1/devices/"+uuid, nil)
+	// then
+	assert.Equal(t, http.StatusOK, code)
+	_, device := doRequest(t, http.MethodGet, s.URL+"/ne/v1/devices/"+uuid, nil)
+	assert.Equal(t, "DEPROVISIONING", device["status"])
+	_, device = doRequest(t, http.MethodGet, s.URL+"/ne/v1/devices/"+uuid, nil)
+	assert.Equal(t, "DEPROVISIONED", device["status"])
+	code, _ = doRequest(t, http.MethodGet, s.URL+"/ne/v1/devices/"+uuid, nil)
+	assert.Equal(t, http.StatusNotFound, code, "Deprovisioned device is removed")
+}
+
+func TestServer_metalParents(t *testing.T) {
+	// given
+	s := NewServer()
+	defer s.Close()
+	projectID := s.Seed(MetalProjects, map[string]interface{}{"name": "test"})
+	otherProjectID := s.Seed(MetalProjects, map[string]interface{}{"name": "other"})
+	// when
+	code, vlan := doRequest(t, http.MethodPost, s.URL+"/metal/v1/projects/"+projectID+"/virtual-networks", map[string]interface{}{
+		"project_id": projectID,
+		"metro":      "sv",
+	})
+	_, list := doRequest(t, http.MethodGet, s.URL+"/metal/v1/projects/"+projectID+"/virtual-networks", nil)
+	_, otherList := doRequest(t, http.MethodGet, s.URL+"/metal/v1/projects/"+otherProjectID+"/virtual-networks", nil)
+	// then
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "sv", vlan["metro_code"])
+	assert.Equal(t, projectID, vlan["assigned_to"].(map[string]interface{})["id"])
+	assert.Len(t, list["virtual_networks"], 1, "Project VLANs are listed")
+	assert.Len(t, otherList["virtual_networks"], 0, "Other project VLANs are not listed")
+
+	// when
+	code, _ = doRequest(t, http.MethodDelete, s.URL+"/metal/v1/virtual-networks/"+vlan["id"].(string), nil)
+	// then
+	assert.Equal(t, http.StatusNoContent, code)
+	code, notFound := doRequest(t, http.MethodGet, s.URL+"/metal/v1/virtual-networks/"+vlan["id"].(string), nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, []interface{}{"Not found"}, notFound["errors"], "Error is in Metal format")
+}
+
+func TestServer_metalPagination(t *testing.T) {
+	// given
+	s := NewServer()
+	defer s.Close()
+	projectID := s.Seed(MetalProjects, map[string]interface{}{"name": "test"})
+	for _, hostname := range []string{"a", "b", "c"} {
+		s.Seed(MetalDevices, map[string]interface{}{"hostname": hostname, "project": map[string]interface{}{"id": projectID}})
+	}
+	devicesURL := s.URL + "/metal/v1/projects/" + projectID + "/devices"
+	// when
+	_, first := doRequest(t, http.MethodGet, devicesURL+"?per_page=2", nil)
+	_, last := doRequest(t, http.MethodGet, devicesURL+"?per_page=2&page=2", nil)
+	_, all := doRequest(t, http.MethodGet, devicesURL, nil)
+	// then
+	assert.Len(t, first["devices"], 2, "First page is full")
+	assert.Equal(t, "a", first["devices"].([]interface{})[0].(map[string]interface{})["hostname"], "Devices are ordered")
+	assert.Equal(t, "/metal/v1/projects/"+projectID+"/devices?page=2&per_page=2",
+		first["meta"].(map[string]interface{})["next"].(map[string]interface{})["href"], "Next page is referenced")
+	assert.Len(t, last["devices"], 1, "Last page has the rest")
+	assert.Nil(t, last["meta"].(map[string]interface{})["next"], "Last page has no next page")
+	assert.Len(t, all["devices"], 3, "List without per_page is not paginated")
+	assert.Equal(t, "2", s.Requests()[len(s.Requests())-3].Query.Get("per_page"), "Query is recorded")
+}
@@ -0,0 +1,266 @@
+// Package telemetry records OpenTelemetry spans of provider operations and
+// API requests and counters of API client events.
+//
+// Telemetry is disabled unless EQUINIX_TELEMETRY_EXPORTER environment
+// variable selects an exporter:
+//
+//   - "otlp" exports spans and metrics to a collector with OTLP over HTTP,
+//     configured with standard OTEL_EXPORTER_OTLP_* environment variables
+//   - "stdout" writes spans and metrics with OpenTelemetry stdout exporters
+//     as JSON to a file set with EQUINIX_TELEMETRY_FILE, or to standard error
+//     of the provider process, as standard output carries plugin protocol
+//
+// Spans and counters are no-ops when telemetry is disabled.
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterEnvVar selects exporter of spans and metrics, "otlp" or "stdout"
+	ExporterEnvVar = "EQUINIX_TELEMETRY_EXPORTER"
+	// FileEnvVar is a path of a file that stdout exporter writes to
+	FileEnvVar = "EQUINIX_TELEMETRY_FILE"
+
+	exporterOTLP   = "otlp"
+	exporterStdout = "stdout"
+
+	instrumentationName = "github.com/equinix/terraform-provider-equinix"
+	serviceName         = "terraform-provider-equinix"
+	shutdownTimeout     = 5 * time.Second
+)
+
+// Attributes are key-value pairs describing a span or a counter. Values
+// should be strings, booleans, integers or floats.
+type Attributes map[string]interface{}
+
+// Span is a single timed operation
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes adds given attributes to the span
+func (s *Span) SetAttributes(attrs Attributes) {
+	s.span.SetAttributes(attrs.keyValues()...)
+}
+
+// End ends the span. Non nil error marks the span as failed.
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// telemetry creates spans and counters with OpenTelemetry tracer and meter
+type telemetry struct {
+	tracer   trace.Tracer
+	meter    metric.Meter
+	shutdown func(context.Context) error
+
+	mu       sync.Mutex
+	counters map[string]instrument.Int64Counter
+}
+
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider, shutdown func(context.Context) error) *telemetry {
+	return &telemetry{
+		tracer:   tracerProvider.Tracer(instrumentationName, trace.WithInstrumentationVersion(version.ProviderVersion)),
+		meter:    meterProvider.Meter(instrumentationName, metric.WithInstrumentationVersion(version.ProviderVersion)),
+		shutdown: shutdown,
+		counters: make(map[string]instrument.Int64Counter),
+	}
+}
+
+var (
+	globalOnce sync.Once
+	global     = newTelemetry(trace.NewNoopTracerProvider(), metric.NewNoopMeterProvider(), nil)
+)
+
+// configured returns telemetry configured with environment variables, with
+// no-op providers when telemetry is disabled
+func configured() *telemetry {
+	globalOnce.Do(func() {
+		exporter := strings.ToLower(os.Getenv(ExporterEnvVar))
+		if exporter == "" {
+			return
+		}
+		t, err := newExportingTelemetry(context.Background(), exporter)
+		if err != nil {
+			log.Printf("[WARN] Telemetry disabled: %s", err)
+			return
+		}
+		global = t
+	})
+	return global
+}
+
+// newExportingTelemetry returns telemetry with SDK providers exporting spans
+// and metrics with given exporter
+func newExportingTelemetry(ctx context.Context, exporter string) (*telemetry, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(version.ProviderVersion)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var spanExporter sdktrace.SpanExporter
+	var metricExporter sdkmetric.Exporter
+	var file *os.File
+	switch exporter {
+	case exporterOTLP:
+		if spanExporter, err = otlptracehttp.New(ctx); err != nil {
+			return nil, err
+		}
+		if metricExporter, err = otlpmetrichttp.New(ctx); err != nil {
+			return nil, err
+		}
+	case exporterStdout:
+		var w io.Writer = os.Stderr
+		if path := os.Getenv(FileEnvVar); path != "" {
+			if file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600); err != nil {
+				return nil, fmt.Errorf("cannot open %s: %s", FileEnvVar, err)
+			}
+			w = file
+		}
+		if spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(w)); err != nil {
+			return nil, err
+		}
+		if metricExporter, err = stdoutmetric.New(stdoutmetric.WithEncoder(json.NewEncoder(w))); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown %s %q, supported are %q and %q", ExporterEnvVar, exporter, exporterOTLP, exporterStdout)
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+	shutdown := func(ctx context.Context) error {
+		traceErr := tracerProvider.Shutdown(ctx)
+		metricErr := meterProvider.Shutdown(ctx)
+		if file != nil {
+			_ = file.Close()
+		}
+		if traceErr != nil {
+			return traceErr
+		}
+		return metricErr
+	}
+	return newTelemetry(tracerProvider, meterProvider, shutdown), nil
+}
+
+// Enabled returns true when telemetry exporter is configured
+func Enabled() bool {
+	return configured().shutdown != nil
+}
+
+// Start starts a span of an operation performed by the provider. The span
+// is a child of a span carried by given context, if any. Returned context
+// carries the new span.
+func Start(ctx context.Context, name string, attrs Attributes) (context.Context, *Span) {
+	return configured().startSpan(ctx, name, trace.SpanKindInternal, attrs)
+}
+
+// StartClient starts a span of a request sent to a remote service
+func StartClient(ctx context.Context, name string, attrs Attributes) (context.Context, *Span) {
+	return configured().startSpan(ctx, name, trace.SpanKindClient, attrs)
+}
+
+// Count increments a counter with given name and attributes
+func Count(ctx context.Context, name string, attrs Attributes) {
+	configured().add(ctx, name, 1, attrs)
+}
+
+// Shutdown exports remaining spans and metrics. It should be called once
+// before the provider process exits, spans ended afterwards are dropped.
+func Shutdown() {
+	t := configured()
+	if t.shutdown == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.shutdown(ctx); err != nil {
+		log.Printf("[WARN] Failed to export telemetry: %s", err)
+	}
+}
+
+func (t *telemetry) startSpan(ctx context.Context, name string, kind trace.SpanKind, attrs Attributes) (context.Context, *Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs.keyValues()...))
+	return ctx, &Span{span: span}
+}
+
+func (t *telemetry) add(ctx context.Context, name string, value int64, attrs Attributes) {
+	counter, err := t.counter(name)
+	if err != nil {
+		log.Printf("[WARN] Failed to create telemetry counter %s: %s", name, err)
+		return
+	}
+	counter.Add(ctx, value, attrs.keyValues()...)
+}
+
+// counter returns counter with given name, created on first use
+func (t *telemetry) counter(name string) (instrument.Int64Counter, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if counter, ok := t.counters[name]; ok {
+		return counter, nil
+	}
+	counter, err := t.meter.Int64Counter(name)
+	if err != nil {
+		return nil, err
+	}
+	t.counters[name] = counter
+	return counter, nil
+}
+
+func (attrs Attributes) keyValues() []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		switch value := v.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(k, value))
+		case bool:
+			kvs = append(kvs, attribute.Bool(k, value))
+		case int:
+			kvs = append(kvs, attribute.Int(k, value))
+		case int64:
+			kvs = append(kvs, attribute.Int64(k, value))
+		case float64:
+			kvs = append(kvs, attribute.Float64(k, value))
+		default:
+			kvs = append(kvs, attribute.String(k, fmt.Sprint(value)))
+		}
+	}
+	return kvs
+}
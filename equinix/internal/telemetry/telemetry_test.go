@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestTelemetry() (*telemetry, *tracetest.SpanRecorder, sdkmetric.Reader) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	return newTelemetry(tracerProvider, meterProvider, tracerProvider.Shutdown), spans, reader
+}
+
+func TestTelemetry_spans(t *testing.T) {
+	// given
+	tel, recorder, _ := newTestTelemetry()
+	// when
+	ctx, parent := tel.startSpan(context.Background(), "create equinix_metal_device", trace.SpanKindInternal, Attributes{"terraform.type": "equinix_metal_device"})
+	_, child := tel.startSpan(ctx, "HTTP POST", trace.SpanKindClient, nil)
+	child.SetAttributes(Attributes{"http.status_code": 422})
+	child.End(errors.New("422 Unprocessable Entity"))
+	parent.End(nil)
+	// then
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "HTTP POST", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID(), "Child span belongs to parent trace")
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID(), "Child span references parent span")
+	assert.False(t, spans[1].Parent().IsValid(), "Parent span is root span")
+	assert.Equal(t, codes.Error, spans[0].Status().Code, "Failed span has error status")
+	assert.Equal(t, "422 Unprocessable Entity", spans[0].Status().Description)
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", 422))
+	assert.Contains(t, spans[1].Attributes(), attribute.String("terraform.type", "equinix_metal_device"))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code, "Successful span has no error status")
+}
+
+func TestTelemetry_counters(t *testing.T) {
+	// given
+	tel, _, reader := newTestTelemetry()
+	// when
+	tel.add(context.Background(), "equinix.http.retries", 1, Attributes{"equinix.service": "metal"})
+	tel.add(context.Background(), "equinix.http.retries", 1, Attributes{"equinix.service": "metal"})
+	tel.add(context.Background(), "equinix.http.retries", 1, Attributes{"equinix.service": "fabric"})
+	metrics := metricdata.ResourceMetrics{}
+	err := reader.Collect(context.Background(), &metrics)
+	// then
+	assert.Nil(t, err)
+	assert.Len(t, metrics.ScopeMetrics, 1)
+	assert.Len(t, metrics.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "equinix.http.retries", metrics.ScopeMetrics[0].Metrics[0].Name)
+	sum, ok := metrics.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	assert.True(t, ok, "Counter is a sum")
+	values := make(map[string]int64)
+	for _, point := range sum.DataPoints {
+		service, _ := point.Attributes.Value("equinix.service")
+		values[service.AsString()] = point.Value
+	}
+	assert.Equal(t, map[string]int64{"metal": 2, "fabric": 1}, values, "Counters are aggregated per attribute set")
+}
+
+func TestTelemetry_disabled(t *testing.T) {
+	// given
+	tel := newTelemetry(trace.NewNoopTracerProvider(), metric.NewNoopMeterProvider(), nil)
+	// when
+	ctx, span := tel.startSpan(context.Background(), "read", trace.SpanKindInternal, nil)
+	span.SetAttributes(Attributes{"key": "value"})
+	span.End(nil)
+	tel.add(ctx, "counter", 1, nil)
+	// then
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid(), "Span is not recorded")
+	assert.Nil(t, tel.shutdown, "Nothing is exported")
+}
@@ -0,0 +1,61 @@
+package equinix
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type contextCRUDFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+type crudFunc = func(*schema.ResourceData, interface{}) error
+
+// withOperationContext converts create, read, update and delete functions of
+// given resources or data sources to context aware ones and binds API
+// requests of every operation to its context. Functions are given provider
+// configuration whose Equinix Metal clients send requests with the context,
+// Equinix Fabric and Network Edge clients are created with it explicitly.
+// The context records request IDs of error responses.
+func withOperationContext(resources map[string]*schema.Resource) {
+	legacy := func(f crudFunc) contextCRUDFunc {
+		return func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return diag.FromErr(f(d, meta))
+		}
+	}
+	bind := func(f contextCRUDFunc) contextCRUDFunc {
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			ctx = withRequestIDRecorder(ctx)
+			if config, ok := meta.(*Config); ok {
+				meta = config.withContext(ctx)
+			}
+			return f(ctx, d, meta)
+		}
+	}
+	for _, r := range resources {
+		if r.Create != nil {
+			r.CreateContext, r.Create = legacy(r.Create), nil
+		}
+		if r.Read != nil {
+			r.ReadContext, r.Read = legacy(r.Read), nil
+		}
+		if r.Update != nil {
+			r.UpdateContext, r.Update = legacy(r.Update), nil
+		}
+		if r.Delete != nil {
+			r.DeleteContext, r.Delete = legacy(r.Delete), nil
+		}
+		if r.CreateContext != nil {
+			r.CreateContext = bind(r.CreateContext)
+		}
+		if r.ReadContext != nil {
+			r.ReadContext = bind(r.ReadContext)
+		}
+		if r.UpdateContext != nil {
+			r.UpdateContext = bind(r.UpdateContext)
+		}
+		if r.DeleteContext != nil {
+			r.DeleteContext = bind(r.DeleteContext)
+		}
+	}
+}
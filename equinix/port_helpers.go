@@ -18,7 +18,7 @@ type ClientPortResource struct {
 }
 
 func getClientPortResource(d *schema.ResourceData, meta interface{}) (*ClientPortResource, *packngo.Response, error) {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	port_id := d.Get("port_id").(string)
 
@@ -0,0 +1,124 @@
+package equinix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	profileEnvVar    = "EQUINIX_PROFILE"
+	configFileEnvVar = "EQUINIX_CONFIG_FILE"
+)
+
+// defaultConfigFile is a location of shared configuration file,
+// relative to user's home directory
+var defaultConfigFile = filepath.Join(".equinix", "config.yaml")
+
+// sharedConfig is a structure of shared configuration file with
+// named profiles, i.e.
+//
+//	profiles:
+//	  sandbox:
+//	    endpoint: https://sandboxapi.equinix.com
+//	    token: someToken
+//	  production:
+//	    client_id: someID
+//	    client_secret: someSecret
+//	    auth_token: someMetalToken
+//	    request_timeout: 60
+//	    poll_max_interval: 30
+type sharedConfig struct {
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile is a named set of provider settings loaded from
+// shared configuration file
+type Profile struct {
+	Endpoint            string `yaml:"endpoint"`
+	MetalEndpoint       string `yaml:"metal_endpoint"`
+	FabricEndpoint      string `yaml:"fabric_endpoint"`
+	NetworkEdgeEndpoint string `yaml:"network_edge_endpoint"`
+	ClientID            string `yaml:"client_id"`
+	ClientSecret        string `yaml:"client_secret"`
+	Token               string `yaml:"token"`
+	AuthToken           string `yaml:"auth_token"`
+	RequestTimeout      int    `yaml:"request_timeout"`
+	MaxRetryWait        int    `yaml:"max_retry_wait_seconds"`
+	PollInterval        int    `yaml:"poll_interval"`
+	PollMaxInterval     int    `yaml:"poll_max_interval"`
+}
+
+// loadProfile reads profile with a given name from shared configuration
+// file. Default file location in user's home directory is used when path
+// is empty.
+func loadProfile(path, name string) (*Profile, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine location of shared configuration file: %s", err)
+		}
+		path = filepath.Join(home, defaultConfigFile)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read profile %q: %s", name, err)
+	}
+	config := sharedConfig{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("cannot parse shared configuration file %s: %s", path, err)
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in shared configuration file %s", name, path)
+	}
+	return &profile, nil
+}
+
+// apply sets profile settings on the configuration fields that were not
+// set with provider arguments or environment variables
+func (p *Profile) apply(c *Config) {
+	if c.BaseURL == "" {
+		c.BaseURL = p.Endpoint
+	}
+	endpoints := map[string]string{
+		serviceMetal:       p.MetalEndpoint,
+		serviceFabric:      p.FabricEndpoint,
+		serviceNetworkEdge: p.NetworkEdgeEndpoint,
+	}
+	for service, endpoint := range endpoints {
+		if endpoint == "" || c.Endpoints[service] != "" {
+			continue
+		}
+		if c.Endpoints == nil {
+			c.Endpoints = make(map[string]string)
+		}
+		c.Endpoints[service] = endpoint
+	}
+	// Credentials of a profile are used only when none are configured,
+	// otherwise a profile token could take precedence over configured
+	// client ID and secret
+	if c.ClientID == "" && c.ClientSecret == "" && c.Token == "" {
+		c.ClientID = p.ClientID
+		c.ClientSecret = p.ClientSecret
+		c.Token = p.Token
+	}
+	if c.AuthToken == "" {
+		c.AuthToken = p.AuthToken
+	}
+	if c.RequestTimeout == 0 && p.RequestTimeout > 0 {
+		c.RequestTimeout = time.Duration(p.RequestTimeout) * time.Second
+	}
+	if c.MaxRetryWait == 0 && p.MaxRetryWait > 0 {
+		c.MaxRetryWait = time.Duration(p.MaxRetryWait) * time.Second
+	}
+	if c.PollInterval == 0 && p.PollInterval > 0 {
+		c.PollInterval = time.Duration(p.PollInterval) * time.Second
+	}
+	if c.PollMaxInterval == 0 && p.PollMaxInterval > 0 {
+		c.PollMaxInterval = time.Duration(p.PollMaxInterval) * time.Second
+	}
+}
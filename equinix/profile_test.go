@@ -0,0 +1,96 @@
+package equinix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSharedConfig = `profiles:
+  sandbox:
+    endpoint: https://sandboxapi.equinix.com
+    token: sandboxToken
+    request_timeout: 60
+  production:
+    client_id: someID
+    client_secret: someSecret
+    auth_token: someMetalToken
+`
+
+func TestProfile_load(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testSharedConfig), 0o600); err != nil {
+		t.Fatalf("cannot write shared configuration file: %s", err)
+	}
+	// when
+	profile, err := loadProfile(path, "sandbox")
+	_, notFoundErr := loadProfile(path, "staging")
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, &Profile{
+		Endpoint:       "https://sandboxapi.equinix.com",
+		Token:          "sandboxToken",
+		RequestTimeout: 60,
+	}, profile, "Profile matches")
+	assert.NotNil(t, notFoundErr, "Error is returned for missing profile")
+}
+
+func TestProfile_apply(t *testing.T) {
+	// given
+	profile := &Profile{
+		Endpoint:        "https://sandboxapi.equinix.com",
+		ClientID:        "profileID",
+		ClientSecret:    "profileSecret",
+		Token:           "profileToken",
+		AuthToken:       "profileMetalToken",
+		RequestTimeout:  60,
+		MaxRetryWait:    20,
+		PollInterval:    5,
+		PollMaxInterval: 30,
+	}
+	config := &Config{
+		ClientID:     "argumentID",
+		ClientSecret: "argumentSecret",
+	}
+	// when
+	profile.apply(config)
+	// then
+	assert.Equal(t, profile.Endpoint, config.BaseURL, "Endpoint is set from profile")
+	assert.Equal(t, "argumentID", config.ClientID, "Configured client ID is kept")
+	assert.Equal(t, "argumentSecret", config.ClientSecret, "Configured client secret is kept")
+	assert.Empty(t, config.Token, "Profile token is not used with configured client credentials")
+	assert.Equal(t, profile.AuthToken, config.AuthToken, "Auth token is set from profile")
+	assert.Equal(t, 60*time.Second, config.RequestTimeout, "Request timeout is set from profile")
+	assert.Equal(t, 20*time.Second, config.MaxRetryWait, "Max retry wait is set from profile")
+	assert.Equal(t, 5*time.Second, config.PollInterval, "Poll interval is set from profile")
+	assert.Equal(t, 30*time.Second, config.PollMaxInterval, "Poll max interval is set from profile")
+}
+
+func TestProfile_applyCredentials(t *testing.T) {
+	// given
+	profile := &Profile{
+		ClientID:     "profileID",
+		ClientSecret: "profileSecret",
+		Token:        "profileToken",
+	}
+	configs := []*Config{
+		{Token: "argumentToken"},
+		{ClientID: "argumentID"},
+		{ClientSecret: "argumentSecret"},
+	}
+	empty := &Config{}
+	// when
+	for _, config := range configs {
+		profile.apply(config)
+	}
+	profile.apply(empty)
+	// then
+	assert.Equal(t, &Config{Token: "argumentToken"}, configs[0], "Profile credentials are not used with configured token")
+	assert.Equal(t, &Config{ClientID: "argumentID"}, configs[1], "Profile credentials are not used with configured client ID")
+	assert.Equal(t, &Config{ClientSecret: "argumentSecret"}, configs[2], "Profile credentials are not used with configured client secret")
+	assert.Equal(t, &Config{ClientID: "profileID", ClientSecret: "profileSecret", Token: "profileToken"}, empty, "Profile credentials are used when none are configured")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/equinix/ecx-go/v2"
 	"github.com/equinix/rest-go"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -30,6 +32,13 @@ const (
 	clientSecretEnvVar   = "EQUINIX_API_CLIENTSECRET"
 	clientTokenEnvVar    = "EQUINIX_API_TOKEN"
 	clientTimeoutEnvVar  = "EQUINIX_API_TIMEOUT"
+	metalEndpointEnvVar  = "EQUINIX_API_METAL_ENDPOINT"
+	fabricEndpointEnvVar = "EQUINIX_API_FABRIC_ENDPOINT"
+	neEndpointEnvVar     = "EQUINIX_API_NETWORK_EDGE_ENDPOINT"
+	caCertFileEnvVar     = "EQUINIX_CA_CERT_FILE"
+	clientCertFileEnvVar = "EQUINIX_CLIENT_CERT_FILE"
+	clientKeyFileEnvVar  = "EQUINIX_CLIENT_KEY_FILE"
+	proxyURLEnvVar       = "EQUINIX_PROXY_URL"
 	metalAuthTokenEnvVar = "METAL_AUTH_TOKEN"
 )
 
@@ -46,13 +55,46 @@ type resourceDataProvider interface {
 func Provider() *schema.Provider {
 	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(profileEnvVar, ""),
+				Description: "Name of the profile in shared configuration file to load endpoints, credentials, request timeout, retry wait and poll intervals from",
+			},
+			"config_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(configFileEnvVar, ""),
+				Description: fmt.Sprintf("Path to shared configuration file with named profiles. Defaults to ~/%s", filepath.ToSlash(defaultConfigFile)),
+			},
 			"endpoint": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				DefaultFunc:  schema.EnvDefaultFunc(endpointEnvVar, DefaultBaseURL),
+				DefaultFunc:  schema.EnvDefaultFunc(endpointEnvVar, ""),
 				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
 				Description:  fmt.Sprintf("The Equinix API base URL to point out desired environment. Defaults to %s", DefaultBaseURL),
 			},
+			"metal_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(metalEndpointEnvVar, ""),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  fmt.Sprintf("The Equinix Metal API URL, including API version path, overrides endpoint for Equinix Metal resources. Defaults to endpoint with %s path", metalBasePath),
+			},
+			"fabric_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(fabricEndpointEnvVar, ""),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "The Equinix Fabric API base URL, overrides endpoint for Equinix Fabric resources",
+			},
+			"network_edge_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(neEndpointEnvVar, ""),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "The Equinix Network Edge API base URL, overrides endpoint for Network Edge resources",
+			},
 			"client_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -80,10 +122,79 @@ func Provider() *schema.Provider {
 			"request_timeout": {
 				Type:         schema.TypeInt,
 				Optional:     true,
-				DefaultFunc:  schema.EnvDefaultFunc(clientTimeoutEnvVar, DefaultTimeout),
+				DefaultFunc:  schema.EnvDefaultFunc(clientTimeoutEnvVar, nil),
 				ValidateFunc: validation.IntAtLeast(1),
 				Description:  fmt.Sprintf("The duration of time, in seconds, that the Equinix Platform API Client should wait before canceling an API request.  Defaults to %d", DefaultTimeout),
 			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(caCertFileEnvVar, ""),
+				Description: "Path to PEM encoded CA certificates trusted in addition to system certificates",
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(clientCertFileEnvVar, ""),
+				RequiredWith: []string{"client_key_file"},
+				Description:  "Path to PEM encoded client certificate used for mutual TLS authentication",
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(clientKeyFileEnvVar, ""),
+				RequiredWith: []string{"client_cert_file"},
+				Description:  "Path to PEM encoded private key of client certificate",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(proxyURLEnvVar, ""),
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "URL of the proxy used for all API requests. Defaults to HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disables verification of API server certificates. Intended only for testing against local stand-ins",
+			},
+			"http_trace_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(httpTraceEnvVar, ""),
+				Description: "Path to a file where redacted traces of all API calls are appended as JSON lines",
+			},
+			"token_cache_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(tokenCacheFileEnvVar, ""),
+				Description: "Path to a file where OAuth tokens are cached and reused across provider invocations until shortly before they expire",
+			},
+			"validate_credentials": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Validates credentials of every configured service with a cheap API call when the provider is configured",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Rejects all API requests that could create, update or delete resources before they are sent",
+			},
+			"hash_secrets_in_state": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Stores passwords, license tokens, keys and user data of resources in state as salted hashes instead of plaintext values",
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(auditLogPathEnvVar, ""),
+				Description: "Path to a file where all create, update and delete API operations are appended as JSON lines",
+			},
 			"response_max_page_size": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -91,14 +202,84 @@ func Provider() *schema.Provider {
 				Description:  "The maximum number of records in a single response for REST queries that produce paginated responses",
 			},
 			"max_retries": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  10,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+				Description: "Maximum number of retries of failed Equinix Metal, Fabric and Network Edge API requests",
 			},
 			"max_retry_wait_seconds": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  30,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: fmt.Sprintf("Maximum number of seconds to wait before retrying a request. Defaults to %d", DefaultMaxRetryWait),
+			},
+			"catalog_cache_ttl_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of seconds responses of catalog lookups, like plans, metros, device types or seller profiles, are reused for. Zero disables the cache",
+			},
+			"poll_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 120),
+				Description:  "Initial number of seconds between status checks of resources waiting for asynchronous operations. Defaults to resource specific value",
+			},
+			"poll_max_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 120),
+				Description:  fmt.Sprintf("Maximum number of seconds between status checks of resources waiting for asynchronous operations. Defaults to %d", int(defaultPollMaxInterval/time.Second)),
+			},
+			"default_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Tags attached to all taggable Equinix Metal resources",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Tags merged with resource tags on create and update",
+						},
+					},
+				},
+			},
+			"protected_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Equinix Metal resources with any of these tags can't be deleted or replaced",
+			},
+			"rate_limit": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    len(services),
+				Description: "Client side limits of API requests sent to a given service",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(services, false),
+							Description:  fmt.Sprintf("Service that limits apply to. One of %s", strings.Join(services, ", ")),
+						},
+						"requests_per_second": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							ValidateFunc: validation.FloatAtLeast(0),
+							Description:  "Maximum number of requests sent to the service per second, including status polling",
+						},
+						"max_concurrent_mutations": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Maximum number of concurrent create, update and delete requests sent to the service",
+						},
+					},
+				},
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
@@ -121,6 +302,7 @@ func Provider() *schema.Provider {
 			"equinix_metal_organization":         dataSourceMetalOrganization(),
 			"equinix_metal_spot_market_price":    dataSourceSpotMarketPrice(),
 			"equinix_metal_device":               dataSourceMetalDevice(),
+			"equinix_metal_devices":              dataSourceMetalDevices(),
 			"equinix_metal_device_bgp_neighbors": dataSourceMetalDeviceBGPNeighbors(),
 			"equinix_metal_plans":                dataSourceMetalPlans(),
 			"equinix_metal_port":                 dataSourceMetalPort(),
@@ -167,6 +349,15 @@ func Provider() *schema.Provider {
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return configureProvider(ctx, d, provider)
 	}
+	for name, r := range provider.ResourcesMap {
+		if strings.HasPrefix(name, "equinix_metal_") {
+			withMetalDiagnostics(r)
+		}
+	}
+	withOperationContext(provider.ResourcesMap)
+	withOperationContext(provider.DataSourcesMap)
+	traceResources(provider.ResourcesMap, false)
+	traceResources(provider.DataSourcesMap, true)
 	return provider
 }
 
@@ -184,6 +375,50 @@ func configureProvider(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 		PageSize:       d.Get("response_max_page_size").(int),
 		MaxRetries:     d.Get("max_retries").(int),
 		MaxRetryWait:   time.Duration(mrws) * time.Second,
+		DefaultTags:    expandListToStringList(d.Get("default_tags.0.tags").([]interface{})),
+		ProtectedTags:  expandListToStringList(d.Get("protected_tags").([]interface{})),
+		Endpoints: map[string]string{
+			serviceMetal:       d.Get("metal_endpoint").(string),
+			serviceFabric:      d.Get("fabric_endpoint").(string),
+			serviceNetworkEdge: d.Get("network_edge_endpoint").(string),
+		},
+		CACertFile:         d.Get("ca_cert_file").(string),
+		ClientCertFile:     d.Get("client_cert_file").(string),
+		ClientKeyFile:      d.Get("client_key_file").(string),
+		ProxyURL:           d.Get("proxy_url").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		HTTPTraceFile:      d.Get("http_trace_file").(string),
+		TokenCacheFile:     d.Get("token_cache_file").(string),
+		ReadOnly:           d.Get("read_only").(bool),
+		AuditLogPath:       d.Get("audit_log_path").(string),
+		CatalogCacheTTL:    time.Duration(d.Get("catalog_cache_ttl_seconds").(int)) * time.Second,
+		PollInterval:       time.Duration(d.Get("poll_interval").(int)) * time.Second,
+		PollMaxInterval:    time.Duration(d.Get("poll_max_interval").(int)) * time.Second,
+		HashSecrets:        d.Get("hash_secrets_in_state").(bool),
+	}
+	rateLimits, diags := expandProviderRateLimits(d.Get("rate_limit").([]interface{}))
+	if diags.HasError() {
+		return nil, diags
+	}
+	config.RateLimits = rateLimits
+	if profileName := d.Get("profile").(string); profileName != "" {
+		profile, err := loadProfile(d.Get("config_file").(string), profileName)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		profile.apply(&config)
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = time.Duration(DefaultTimeout) * time.Second
+	}
+	if config.MaxRetryWait == 0 {
+		config.MaxRetryWait = time.Duration(DefaultMaxRetryWait) * time.Second
+	}
+	if config.PollMaxInterval == 0 {
+		config.PollMaxInterval = defaultPollMaxInterval
 	}
 
 	config.terraformVersion = p.TerraformVersion
@@ -200,7 +435,39 @@ func configureProvider(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 	if err := config.Load(stopCtx); err != nil {
 		return nil, diag.FromErr(err)
 	}
-	return &config, nil
+	if d.Get("validate_credentials").(bool) {
+		if diags = append(diags, config.ValidateCredentials(stopCtx)...); diags.HasError() {
+			return nil, diags
+		}
+	}
+	return &config, diags
+}
+
+// expandProviderRateLimits returns limits of rate_limit blocks per service.
+// Schema of lists can't validate uniqueness of their elements, so blocks of
+// duplicate services are rejected here.
+func expandProviderRateLimits(limits []interface{}) (map[string]RateLimit, diag.Diagnostics) {
+	transformed := make(map[string]RateLimit, len(limits))
+	for i, v := range limits {
+		limit, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		service := limit["service"].(string)
+		if _, ok := transformed[service]; ok {
+			return nil, diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Duplicate rate_limit service %q", service),
+				Detail:        "Limits of a service can be set in a single rate_limit block.",
+				AttributePath: cty.GetAttrPath("rate_limit").IndexInt(i).GetAttr("service"),
+			}}
+		}
+		transformed[service] = RateLimit{
+			RequestsPerSecond:      limit["requests_per_second"].(float64),
+			MaxConcurrentMutations: limit["max_concurrent_mutations"].(int),
+		}
+	}
+	return transformed, nil
 }
 
 var resourceDefaultTimeouts = &schema.ResourceTimeout{
@@ -1,18 +1,24 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/equinix/ecx-go/v2"
 	"github.com/equinix/rest-go"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -388,6 +394,11 @@ func TestProvider_schemaSetToMap(t *testing.T) {
 func testAccPreCheck(t *testing.T) {
 	var err error
 
+	if recorderMode() == recorderModeReplay {
+		// interactions are served from cassette, credentials are not needed
+		return
+	}
+
 	if _, err = getFromEnv(clientTokenEnvVar); err != nil {
 		_, err = getFromEnv(clientIDEnvVar)
 		if err == nil {
@@ -405,6 +416,102 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// testUnitPreCheck skips lifecycle unit tests when there is no Terraform
+// binary to run them with
+func testUnitPreCheck(t *testing.T) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("Terraform binary is required to run lifecycle unit tests, set TF_ACC_TERRAFORM_PATH or add terraform to PATH")
+	}
+}
+
+// testFakeAPIProviderConfig returns provider configuration pointing
+// to fake API server under given URL
+func testFakeAPIProviderConfig(url string) string {
+	return fmt.Sprintf(`
+provider "equinix" {
+  endpoint   = "%s"
+  token      = "fakeToken"
+  auth_token = "fakeAuthToken"
+}
+`, url)
+}
+
+// testFakeAPIConfig returns loaded provider configuration pointing to fake
+// API server under given URL
+func testFakeAPIConfig(t *testing.T, url string) *Config {
+	config := &Config{
+		BaseURL:         url,
+		Token:           "fakeToken",
+		AuthToken:       "fakeAuthToken",
+		PollInterval:    10 * time.Millisecond,
+		PollMaxInterval: 10 * time.Millisecond,
+	}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	return config
+}
+
+// testRecorderConfig returns loaded provider configuration for a test of
+// API interactions recorded in given cassette of test-fixtures/cassettes.
+// The test is skipped unless recorder is enabled. Credentials are read from
+// the same environment variables as in acceptance tests.
+func testRecorderConfig(t *testing.T, cassette string) *Config {
+	if recorderMode() == "" {
+		t.Skipf("Recorder is disabled, set %s to record or replay API interactions", recorderModeEnvVar)
+	}
+	t.Setenv(recorderCassetteEnvVar, filepath.Join("test-fixtures", "cassettes", cassette))
+	testAccPreCheck(t)
+	config := &Config{
+		BaseURL:      getFromEnvDefault(endpointEnvVar, DefaultBaseURL),
+		Token:        getFromEnvDefault(clientTokenEnvVar, ""),
+		ClientID:     getFromEnvDefault(clientIDEnvVar, ""),
+		ClientSecret: getFromEnvDefault(clientSecretEnvVar, ""),
+		AuthToken:    getFromEnvDefault(metalAuthTokenEnvVar, ""),
+	}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("cannot load configuration: %s", err)
+	}
+	return config
+}
+
+// testFakeAPILifecycle creates, refreshes and destroys a resource with given
+// configuration through its schema, CRUD functions and waiters, the same way
+// Terraform does, but without Terraform binary. State after refresh is
+// returned.
+func testFakeAPILifecycle(t *testing.T, r *schema.Resource, meta interface{}, raw map[string]interface{}) *terraform.InstanceState {
+	ctx := context.Background()
+	diff, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatalf("plan failed: %s", err)
+	}
+	state, diags := r.Apply(ctx, nil, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if state == nil || state.ID == "" {
+		t.Fatalf("create did not set resource ID")
+	}
+	state, diags = r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if state == nil || state.ID == "" {
+		t.Fatalf("read removed created resource")
+	}
+	destroyed, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+	if diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if destroyed != nil && destroyed.ID != "" {
+		t.Fatalf("delete did not remove resource ID")
+	}
+	return state
+}
+
 func newTestAccConfig(ctx map[string]interface{}) *testAccConfig {
 	return &testAccConfig{
 		ctx:    ctx,
@@ -467,3 +574,18 @@ func setSchemaValueIfNotEmpty(key string, value interface{}, d *schema.ResourceD
 	}
 	return nil
 }
+
+func TestProvider_expandRateLimitsDuplicate(t *testing.T) {
+	// given
+	limits := []interface{}{
+		map[string]interface{}{"service": serviceMetal, "requests_per_second": 5.0, "max_concurrent_mutations": 0},
+		map[string]interface{}{"service": serviceFabric, "requests_per_second": 1.0, "max_concurrent_mutations": 0},
+		map[string]interface{}{"service": serviceMetal, "requests_per_second": 0.0, "max_concurrent_mutations": 2},
+	}
+	// when
+	result, diags := expandProviderRateLimits(limits)
+	// then
+	assert.Nil(t, result, "Limits are not returned")
+	assert.True(t, diags.HasError(), "Duplicate service is rejected")
+	assert.Equal(t, cty.GetAttrPath("rate_limit").IndexInt(2).GetAttr("service"), diags[0].AttributePath, "Error is reported on duplicate block")
+}
@@ -0,0 +1,220 @@
+package equinix
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readCachePaths are URL path patterns of read only catalog endpoints which
+// responses are cached, per service. Resources managed by the provider are
+// never cached, so they are read fresh after every change.
+var readCachePaths = map[string][]*regexp.Regexp{
+	serviceMetal: {
+		regexp.MustCompile(`/v1/plans$`),
+		regexp.MustCompile(`/v1/facilities$`),
+		regexp.MustCompile(`/v1/locations/metros$`),
+		regexp.MustCompile(`/v1/operating-systems$`),
+		regexp.MustCompile(`/v1/projects/[^/]+/plans$`),
+	},
+	serviceFabric: {
+		regexp.MustCompile(`/ecx/v3/l2/serviceprofiles/services$`),
+	},
+	serviceNetworkEdge: {
+		regexp.MustCompile(`/ne/v1/deviceTypes$`),
+	},
+}
+
+// readCacheEntry is a response of cached request
+type readCacheEntry struct {
+	path       string
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+// response returns a copy of cached response to a given request
+func (e *readCacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// readCacheCall is a request in flight, concurrent identical requests wait
+// for its result instead of being sent
+type readCacheCall struct {
+	done  chan struct{}
+	entry *readCacheEntry
+	err   error
+	// canceled is set when the request failed because its context was
+	// canceled, waiting requests then send their own requests
+	canceled bool
+}
+
+// readCache holds responses of catalog requests for a short time, so
+// lookups repeated by many resources of a single Terraform run are sent
+// once. The cache is shared by API clients of a provider instance.
+type readCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*readCacheEntry
+	calls   map[string]*readCacheCall
+}
+
+func newReadCache(ttl time.Duration) *readCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &readCache{
+		ttl:     ttl,
+		entries: make(map[string]*readCacheEntry),
+		calls:   make(map[string]*readCacheCall),
+	}
+}
+
+// get returns cached response or sends the request, unless identical
+// request is already in flight. Requests waiting for the one in flight stop
+// when their own context is done. Only successful responses are cached.
+func (c *readCache) get(key string, req *http.Request, send func() (*http.Response, error)) (*http.Response, error) {
+	for {
+		c.mu.Lock()
+		if entry, ok := c.entries[key]; ok {
+			if time.Now().Before(entry.expires) {
+				c.mu.Unlock()
+				return entry.response(req), nil
+			}
+			delete(c.entries, key)
+		}
+		call, ok := c.calls[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if call.canceled {
+			continue
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.entry.response(req), nil
+	}
+	call := &readCacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.entry, call.err = c.send(req, send)
+	call.canceled = call.err != nil && req.Context().Err() != nil
+
+	c.mu.Lock()
+	if call.err == nil && call.entry.statusCode == http.StatusOK {
+		c.entries[key] = call.entry
+	}
+	delete(c.calls, key)
+	c.mu.Unlock()
+	close(call.done)
+
+	if call.err != nil {
+		return nil, call.err
+	}
+	return call.entry.response(req), nil
+}
+
+func (c *readCache) send(req *http.Request, send func() (*http.Response, error)) (*readCacheEntry, error) {
+	resp, err := send()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &readCacheEntry{
+		path:       req.URL.Path,
+		status:     resp.Status,
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+		expires:    time.Now().Add(c.ttl),
+	}, nil
+}
+
+// invalidate drops cached responses of a given path, paths below it and
+// paths above it, i.e. collections listing a modified resource
+func (c *readCache) invalidate(path string) {
+	path = strings.TrimSuffix(path, "/")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if entry.path == path || strings.HasPrefix(entry.path, path+"/") || strings.HasPrefix(path, entry.path+"/") {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// readCacheTransport serves GET requests of catalog endpoints from read
+// cache. Other requests are sent as they are, and requests that modify
+// resources invalidate cached responses of the modified resources.
+type readCacheTransport struct {
+	cache *readCache
+	paths []*regexp.Regexp
+	next  http.RoundTripper
+}
+
+// newReadCacheTransport wraps given transport of a service with read cache
+// or returns the transport as it is when cache is disabled
+func newReadCacheTransport(cache *readCache, service string, next http.RoundTripper) http.RoundTripper {
+	if cache == nil || len(readCachePaths[service]) == 0 {
+		return next
+	}
+	return &readCacheTransport{
+		cache: cache,
+		paths: readCachePaths[service],
+		next:  next,
+	}
+}
+
+func (t *readCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadOnlyMethod(req.Method) {
+		t.cache.invalidate(req.URL.Path)
+		resp, err := t.next.RoundTrip(req)
+		// responses cached while request was processed could be stale
+		t.cache.invalidate(req.URL.Path)
+		return resp, err
+	}
+	if req.Method != http.MethodGet || !t.cacheable(req.URL.Path) {
+		return t.next.RoundTrip(req)
+	}
+	return t.cache.get(req.URL.String(), req, func() (*http.Response, error) {
+		return t.next.RoundTrip(req)
+	})
+}
+
+func (t *readCacheTransport) cacheable(path string) bool {
+	for _, re := range t.paths {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
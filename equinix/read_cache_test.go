@@ -0,0 +1,159 @@
+package equinix
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCountingRoundTripper(sent *int32, delay time.Duration) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(sent, 1)
+		time.Sleep(delay)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"plans":[]}`)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestReadCacheTransport_coalesce(t *testing.T) {
+	// given
+	var sent int32
+	transport := newReadCacheTransport(newReadCache(time.Minute), serviceMetal, newCountingRoundTripper(&sent, 50*time.Millisecond))
+	bodies := make([]string, 10)
+	// when
+	var wg sync.WaitGroup
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/plans?include=available_in"))
+			if err == nil {
+				body, _ := ioutil.ReadAll(resp.Body)
+				bodies[i] = string(body)
+			}
+		}(i)
+	}
+	wg.Wait()
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/plans?include=available_in"))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/devices/123"))
+	// then
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sent), "Catalog request is sent once, other requests are not cached")
+	for _, body := range bodies {
+		assert.Equal(t, `{"plans":[]}`, body, "Every caller receives response body")
+	}
+}
+
+func TestReadCacheTransport_invalidate(t *testing.T) {
+	// given
+	var sent int32
+	transport := newReadCacheTransport(newReadCache(time.Minute), serviceMetal, newCountingRoundTripper(&sent, 0))
+	projectURL := "https://api.equinix.com/metal/v1/projects/6b2c6f8a-1a6b-4b5c-9d63-4c3a2f5a7e10"
+	// when
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, projectURL+"/plans"))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodPut, projectURL))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, projectURL+"/plans"))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, projectURL))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, projectURL))
+	// then
+	assert.Equal(t, int32(5), atomic.LoadInt32(&sent), "Plans are fetched again after project update, project is not cached")
+}
+
+func TestReadCacheTransport_invalidateParent(t *testing.T) {
+	// given
+	var sent int32
+	transport := newReadCacheTransport(newReadCache(time.Minute), serviceFabric, newCountingRoundTripper(&sent, 0))
+	profilesURL := "https://api.equinix.com/ecx/v3/l2/serviceprofiles/services"
+	// when
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, profilesURL))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodPut, profilesURL+"/4d3d8dc4-98b9-4ec1-a3d6-2ba4c4ac1a20"))
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, profilesURL))
+	// then
+	assert.Equal(t, int32(3), atomic.LoadInt32(&sent), "Collection is fetched again after update of its member")
+}
+
+func TestReadCacheTransport_expiry(t *testing.T) {
+	// given
+	var sent int32
+	transport := newReadCacheTransport(newReadCache(10*time.Millisecond), serviceNetworkEdge, newCountingRoundTripper(&sent, 0))
+	// when
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/ne/v1/deviceTypes"))
+	time.Sleep(20 * time.Millisecond)
+	_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/ne/v1/deviceTypes"))
+	// then
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sent), "Expired response is not reused")
+}
+
+func TestReadCacheTransport_waitingRequestCanceled(t *testing.T) {
+	// given
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	transport := newReadCacheTransport(newReadCache(time.Minute), serviceMetal, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return newCountingRoundTripper(new(int32), 0).RoundTrip(req)
+	}))
+	go func() {
+		_, _ = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/plans"))
+	}()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// when
+	_, err := transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/plans").WithContext(ctx))
+	// then
+	assert.Equal(t, context.DeadlineExceeded, err, "Waiting request stops when its context is done")
+}
+
+func TestReadCacheTransport_sentRequestCanceled(t *testing.T) {
+	// given
+	var sent int32
+	started := make(chan struct{})
+	transport := newReadCacheTransport(newReadCache(time.Minute), serviceMetal, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&sent, 1) == 1 {
+			close(started)
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return newCountingRoundTripper(new(int32), 0).RoundTrip(req)
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	var sentErr error
+	sentDone := make(chan struct{})
+	go func() {
+		defer close(sentDone)
+		_, sentErr = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/plans").WithContext(ctx))
+	}()
+	<-started
+	// when
+	var waitingErr error
+	var body []byte
+	waitingDone := make(chan struct{})
+	go func() {
+		defer close(waitingDone)
+		var resp *http.Response
+		if resp, waitingErr = transport.RoundTrip(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/plans")); waitingErr == nil {
+			body, _ = ioutil.ReadAll(resp.Body)
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-sentDone
+	<-waitingDone
+	// then
+	assert.Equal(t, context.Canceled, sentErr, "Canceled request returns error of its context")
+	assert.Nil(t, waitingErr, "Waiting request does not return error of canceled request")
+	assert.Equal(t, `{"plans":[]}`, string(body), "Waiting request sends its own request")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sent))
+}
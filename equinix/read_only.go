@@ -0,0 +1,42 @@
+package equinix
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ReadOnlyError is returned for requests that would modify resources when
+// the provider is in read only mode
+type ReadOnlyError struct {
+	Method string
+	URL    string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("provider is configured with read_only = true, refusing to send %s %s", e.Method, e.URL)
+}
+
+// readOnlyTransport rejects all requests but GET, HEAD and OPTIONS before
+// they are sent
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// newReadOnlyTransport wraps given transport with read only checks or
+// returns the transport as it is when read only mode is disabled
+func newReadOnlyTransport(readOnly bool, next http.RoundTripper) http.RoundTripper {
+	if !readOnly {
+		return next
+	}
+	return &readOnlyTransport{next: next}
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadOnlyMethod(req.Method) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &ReadOnlyError{Method: req.Method, URL: req.URL.String()}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,44 @@
+package equinix
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	// given
+	sent := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("{}")),
+			Request:    req,
+		}, nil
+	})
+	config := &Config{MaxRetries: 3, MaxRetryWait: time.Millisecond, ReadOnly: true}
+	client := config.newRetryableHTTPClient(serviceMetal, newReadOnlyTransport(config.ReadOnly, next), 0)
+	// when
+	_, deleteErr := client.Do(mustNewRequest(t, http.MethodDelete, "https://api.equinix.com/metal/v1/devices/123"))
+	getResp, getErr := client.Do(mustNewRequest(t, http.MethodGet, "https://api.equinix.com/metal/v1/devices/123"))
+	// then
+	var readOnlyErr *ReadOnlyError
+	assert.True(t, errors.As(deleteErr, &readOnlyErr), "Delete is rejected with read only error")
+	assert.Nil(t, getErr, "Get is not rejected")
+	assert.Equal(t, http.StatusOK, getResp.StatusCode)
+	assert.Equal(t, 1, sent, "Only get request is sent and rejected request is not retried")
+}
+
+func mustNewRequest(t *testing.T, method, url string) *http.Request {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("cannot create request: %s", err)
+	}
+	return req
+}
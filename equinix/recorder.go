@@ -0,0 +1,326 @@
+package equinix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	recorderModeEnvVar     = "EQUINIX_RECORDER_MODE"
+	recorderCassetteEnvVar = "EQUINIX_RECORDER_CASSETTE"
+
+	recorderModeRecord = "record"
+	recorderModeReplay = "replay"
+
+	defaultCassettePath = "test-fixtures/cassettes/default.json"
+	redactedValue       = "REDACTED"
+)
+
+// sensitiveHeaders are removed from recorded requests and responses
+var sensitiveHeaders = []string{
+	"Authorization",
+	"X-Auth-Token",
+	"X-Consumer-Token",
+	"Set-Cookie",
+	"Cookie",
+}
+
+// sensitiveBodyKeys are JSON object keys whose string values are redacted in
+// recorded request and response bodies. Keys are matched case insensitively.
+var sensitiveBodyKeys = []string{
+	"access_token",
+	"refresh_token",
+	"client_secret",
+	"password",
+	"token",
+	"licenseToken",
+	"authenticationKey",
+	"authorizationKey",
+	"secretKey",
+	"accessKey",
+	"root_password",
+	"md5",
+}
+
+// cassetteInteraction is a single sanitized request/response pair
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// cassette is a file backed, ordered list of interactions. Cassettes are
+// shared between all clients of a provider process that point to the same file.
+type cassette struct {
+	mu           sync.Mutex
+	path         string
+	Interactions []*cassetteInteraction `json:"interactions"`
+	used         []bool
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*cassette)
+)
+
+// loadCassette returns the cassette stored under given path, loading it from
+// disk on first use. Missing files result in empty cassettes.
+func loadCassette(path string) (*cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	if c, ok := cassettes[path]; ok {
+		return c, nil
+	}
+	c := &cassette{path: path}
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("cannot parse cassette %q: %s", path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("cannot read cassette %q: %s", path, err)
+	}
+	c.used = make([]bool, len(c.Interactions))
+	cassettes[path] = c
+	return c, nil
+}
+
+func (c *cassette) add(i *cassetteInteraction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, i)
+	c.used = append(c.used, true)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, data, 0o644)
+}
+
+// next returns first not yet replayed interaction that matches given request.
+// URLs are matched without scheme and host, so interactions recorded with
+// endpoint overrides are replayed with any endpoint.
+func (c *cassette) next(req cassetteRequest) (*cassetteInteraction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.Interactions {
+		if c.used[i] {
+			continue
+		}
+		r := interaction.Request
+		if r.Method == req.Method && requestURI(r.URL) == requestURI(req.URL) && r.Body == req.Body {
+			c.used[i] = true
+			return interaction, true
+		}
+	}
+	return nil, false
+}
+
+// requestURI returns path and query of given URL
+func requestURI(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.RequestURI()
+}
+
+// recorderTransport is a http.RoundTripper that saves sanitized API
+// interactions to a cassette (record mode) or serves them back from
+// a cassette without reaching the network (replay mode)
+type recorderTransport struct {
+	mode     string
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+// newRecorderTransport wraps given transport with recorder when recorder
+// mode is enabled with EQUINIX_RECORDER_MODE environment variable
+func newRecorderTransport(next http.RoundTripper) (http.RoundTripper, error) {
+	mode := recorderMode()
+	if mode == "" {
+		return next, nil
+	}
+	path := getEnvDefault(recorderCassetteEnvVar, defaultCassettePath)
+	c, err := loadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] HTTP recorder enabled in %s mode with cassette %s", mode, path)
+	return &recorderTransport{
+		mode:     mode,
+		cassette: c,
+		next:     next,
+	}, nil
+}
+
+// recorderMode returns normalized recorder mode or empty string when
+// recorder is disabled
+func recorderMode() string {
+	switch mode := strings.ToLower(os.Getenv(recorderModeEnvVar)); mode {
+	case recorderModeRecord, recorderModeReplay:
+		return mode
+	default:
+		return ""
+	}
+}
+
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recReq := cassetteRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   sanitizeBody(reqBody),
+	}
+	if t.mode == recorderModeReplay {
+		interaction, ok := t.cassette.next(recReq)
+		if !ok {
+			return nil, fmt.Errorf("recorder: no interaction found in cassette %q for %s %s", t.cassette.path, recReq.Method, recReq.URL)
+		}
+		return interaction.Response.toHTTPResponse(req), nil
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	interaction := &cassetteInteraction{
+		Request: recReq,
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     sanitizeHeader(resp.Header),
+			Body:       sanitizeBody(respBody),
+		},
+	}
+	if err := t.cassette.add(interaction); err != nil {
+		log.Printf("[WARN] recorder: failed to save cassette %q: %s", t.cassette.path, err)
+	}
+	return resp, nil
+}
+
+func (r cassetteResponse) toHTTPResponse(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads request body and restores it so request can be
+// still sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func sanitizeHeader(header http.Header) http.Header {
+	sanitized := header.Clone()
+	for _, h := range sensitiveHeaders {
+		sanitized.Del(h)
+	}
+	return sanitized
+}
+
+// sanitizeBody redacts sensitive values from JSON bodies. Non JSON bodies are
+// returned as they are.
+func sanitizeBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	sanitized, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(sanitized)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if _, ok := val.(string); ok && isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(val)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	for _, k := range sensitiveBodyKeys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func getEnvDefault(varName, defaultValue string) string {
+	if v := os.Getenv(varName); v != "" {
+		return v
+	}
+	return defaultValue
+}
@@ -0,0 +1,83 @@
+package equinix
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_recordAndReplay(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(`{"id":"abc","password":"secret"}`))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv(recorderCassetteEnvVar, path)
+	t.Setenv(recorderModeEnvVar, recorderModeRecord)
+	recorder, err := newRecorderTransport(http.DefaultTransport)
+	assert.Nil(t, err, "Recorder is created")
+	// when
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/metal/v1/devices", strings.NewReader(`{"hostname":"test"}`))
+	req.Header.Set("X-Auth-Token", "someToken")
+	resp, err := recorder.RoundTrip(req)
+	// then
+	assert.Nil(t, err, "Request is recorded")
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"id":"abc","password":"secret"}`, string(body), "Recorded response body is not altered")
+	saved, _ := ioutil.ReadFile(path)
+	assert.NotContains(t, string(saved), "secret", "Cassette does not contain secrets")
+	assert.NotContains(t, string(saved), "someToken", "Cassette does not contain tokens")
+
+	// given
+	server.Close()
+	cassettesMu.Lock()
+	delete(cassettes, path)
+	cassettesMu.Unlock()
+	t.Setenv(recorderModeEnvVar, recorderModeReplay)
+	recorder, err = newRecorderTransport(http.DefaultTransport)
+	assert.Nil(t, err, "Replaying recorder is created")
+	// when
+	req, _ = http.NewRequest(http.MethodPost, DefaultBaseURL+"/metal/v1/devices", strings.NewReader(`{"hostname":"test"}`))
+	resp, err = recorder.RoundTrip(req)
+	// then
+	assert.Nil(t, err, "Request to other endpoint is replayed")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "req-1", resp.Header.Get("X-Request-Id"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"id":"abc","password":"REDACTED"}`, string(body))
+	_, err = recorder.RoundTrip(req)
+	assert.NotNil(t, err, "Interaction is replayed only once")
+}
+
+func TestRecorder_sanitizeBody(t *testing.T) {
+	// given
+	input := []byte(`{"deviceName":"test","licenseToken":"abc","userPassword":{"password":"pass"},"list":[{"authenticationKey":"key"}]}`)
+	expected := `{"deviceName":"test","licenseToken":"REDACTED","list":[{"authenticationKey":"REDACTED"}],"userPassword":{"password":"REDACTED"}}`
+	// when
+	result := sanitizeBody(input)
+	// then
+	assert.Equal(t, expected, result, "Sensitive values are redacted")
+	assert.Equal(t, "plain text", sanitizeBody([]byte("plain text")), "Non JSON body is not altered")
+}
+
+func TestSSHKeyPair_recorded(t *testing.T) {
+	// given
+	t.Setenv(recorderModeEnvVar, recorderModeReplay)
+	// when
+	key, err := testSSHKeyPair(t, "user")
+	sameKey, _ := testSSHKeyPair(t, "user")
+	otherKey, _ := testSSHKeyPair(t, "project")
+	// then
+	assert.Nil(t, err, "Key is generated")
+	assert.True(t, strings.HasPrefix(key, "ssh-ed25519 "), "Key is in authorized keys format")
+	assert.Equal(t, key, sameKey, "Recorded keys are deterministic")
+	assert.NotEqual(t, key, otherKey, "Keys with different names differ")
+}
@@ -0,0 +1,64 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type requestIDsCtxKey struct{}
+
+// requestIDRecorder keeps request IDs of error responses received during
+// a single resource or data source operation. Errors of rest-go client do
+// not carry response headers, so request ID of Equinix Fabric and Network
+// Edge error is looked up in the recorder of the operation that failed.
+type requestIDRecorder struct {
+	mu  sync.Mutex
+	ids map[int]string
+}
+
+// withRequestIDRecorder returns context carrying new request ID recorder
+func withRequestIDRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDsCtxKey{}, &requestIDRecorder{ids: make(map[int]string)})
+}
+
+// requestIDOfStatus returns request ID of the latest response with given
+// HTTP status received with given context, or empty string when it is
+// unknown
+func requestIDOfStatus(ctx context.Context, status int) string {
+	r, ok := ctx.Value(requestIDsCtxKey{}).(*requestIDRecorder)
+	if !ok {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ids[status]
+}
+
+func (r *requestIDRecorder) add(status int, requestID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids[status] = requestID
+}
+
+// requestIDTransport is a http.RoundTripper that records request IDs of
+// error responses in the recorder carried by request context
+type requestIDTransport struct {
+	next http.RoundTripper
+}
+
+func newRequestIDTransport(next http.RoundTripper) http.RoundTripper {
+	return &requestIDTransport{next: next}
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+	r, ok := req.Context().Value(requestIDsCtxKey{}).(*requestIDRecorder)
+	if requestID := resp.Header.Get(requestIDHeader); ok && requestID != "" {
+		r.add(resp.StatusCode, requestID)
+	}
+	return resp, nil
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/equinix/ecx-go/v2"
-	"github.com/equinix/rest-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -664,28 +663,28 @@ func resourceECXL2ConnectionCreate(ctx context.Context, d *schema.ResourceData,
 	var primaryID, secondaryID *string
 	var err error
 	if secondary != nil {
-		primaryID, secondaryID, err = conf.ecx.CreateL2RedundantConnection(*primary, *secondary)
+		primaryID, secondaryID, err = conf.ecxClient(ctx).CreateL2RedundantConnection(*primary, *secondary)
 	} else {
-		primaryID, err = conf.ecx.CreateL2Connection(*primary)
+		primaryID, err = conf.ecxClient(ctx).CreateL2Connection(*primary)
 	}
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ConnectionSchemaNames)
 	}
 	d.SetId(ecx.StringValue(primaryID))
 	waitConfigs := []*resource.StateChangeConf{
-		createConnectionStatusProvisioningWaitConfiguration(conf.ecx.GetL2Connection, d.Id(), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
+		createConnectionStatusProvisioningWaitConfiguration(conf.ecxClient(ctx).GetL2Connection, d.Id(), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
 	}
 	if ecx.StringValue(secondaryID) != "" {
 		d.Set(ecxL2ConnectionSchemaNames["RedundantUUID"], secondaryID)
 		waitConfigs = append(waitConfigs,
-			createConnectionStatusProvisioningWaitConfiguration(conf.ecx.GetL2Connection, ecx.StringValue(secondaryID), 2*time.Second, d.Timeout(schema.TimeoutCreate)),
+			createConnectionStatusProvisioningWaitConfiguration(conf.ecxClient(ctx).GetL2Connection, ecx.StringValue(secondaryID), 2*time.Second, d.Timeout(schema.TimeoutCreate)),
 		)
 	}
 	for _, config := range waitConfigs {
 		if config == nil {
 			continue
 		}
-		if _, err := config.WaitForStateContext(ctx); err != nil {
+		if _, err := conf.stateWaiter(ctx, "ecx_l2_connection", config).WaitForStateContext(ctx); err != nil {
 			return diag.Errorf("error waiting for connection (%s) to be created: %s", d.Id(), err)
 		}
 	}
@@ -700,7 +699,7 @@ func resourceECXL2ConnectionRead(ctx context.Context, d *schema.ResourceData, m
 	var primary *ecx.L2Connection
 	var secondary *ecx.L2Connection
 
-	primary, err = conf.ecx.GetL2Connection(d.Id())
+	primary, err = conf.ecxClient(ctx).GetL2Connection(d.Id())
 	if err != nil {
 		return diag.Errorf("cannot fetch primary connection due to %v", err)
 	}
@@ -718,7 +717,7 @@ func resourceECXL2ConnectionRead(ctx context.Context, d *schema.ResourceData, m
 	// Implementing a l2_connection datasource will require search for secondary connection before using
 	// resourceECXL2ConnectionRead or explicitly request the names or identifiers of each connection
 	if redID, ok := d.GetOk(ecxL2ConnectionSchemaNames["RedundantUUID"]); ok {
-		secondary, err = conf.ecx.GetL2Connection(redID.(string))
+		secondary, err = conf.ecxClient(ctx).GetL2Connection(redID.(string))
 		if err != nil {
 			return diag.Errorf("cannot fetch secondary connection due to %v", err)
 		}
@@ -732,7 +731,7 @@ func resourceECXL2ConnectionRead(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	if err := updateECXL2ConnectionResource(primary, secondary, d); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ConnectionSchemaNames)
 	}
 	return diags
 }
@@ -746,15 +745,15 @@ func resourceECXL2ConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 		ecxL2ConnectionSchemaNames["SpeedUnit"],
 	}
 	primaryChanges := getResourceDataChangedKeys(supportedChanges, d)
-	primaryUpdateReq := conf.ecx.NewL2ConnectionUpdateRequest(d.Id())
+	primaryUpdateReq := conf.ecxClient(ctx).NewL2ConnectionUpdateRequest(d.Id())
 	if err := fillFabricL2ConnectionUpdateRequest(primaryUpdateReq, primaryChanges).Execute(); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ConnectionSchemaNames)
 	}
 	if redID, ok := d.GetOk(ecxL2ConnectionSchemaNames["RedundantUUID"]); ok {
 		secondaryChanges := getResourceDataListElementChanges(supportedChanges, ecxL2ConnectionSchemaNames["SecondaryConnection"], 0, d)
-		secondaryUpdateReq := conf.ecx.NewL2ConnectionUpdateRequest(redID.(string))
+		secondaryUpdateReq := conf.ecxClient(ctx).NewL2ConnectionUpdateRequest(redID.(string))
 		if err := fillFabricL2ConnectionUpdateRequest(secondaryUpdateReq, secondaryChanges).Execute(); err != nil {
-			return diag.FromErr(err)
+			return diagFromAPIError(ctx, err, ecxL2ConnectionSchemaNames)
 		}
 	}
 	diags = append(diags, resourceECXL2ConnectionRead(ctx, d, m)...)
@@ -764,36 +763,30 @@ func resourceECXL2ConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 func resourceECXL2ConnectionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	if err := conf.ecx.DeleteL2Connection(d.Id()); err != nil {
-		restErr, ok := err.(rest.Error)
-		if ok {
-			// IC-LAYER2-4021 = Connection already deleted
-			if hasApplicationErrorCode(restErr.ApplicationErrors, "IC-LAYER2-4021") {
-				return diags
-			}
+	if err := conf.ecxClient(ctx).DeleteL2Connection(d.Id()); err != nil {
+		// IC-LAYER2-4021 = Connection already deleted
+		if hasAPIErrorCode(err, "IC-LAYER2-4021") {
+			return diags
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ConnectionSchemaNames)
 	}
 	waitConfigs := []*resource.StateChangeConf{
-		createConnectionStatusDeleteWaitConfiguration(conf.ecx.GetL2Connection, d.Id(), 5*time.Second, d.Timeout(schema.TimeoutDelete)),
+		createConnectionStatusDeleteWaitConfiguration(conf.ecxClient(ctx).GetL2Connection, d.Id(), 5*time.Second, d.Timeout(schema.TimeoutDelete)),
 	}
 	if redID, ok := d.GetOk(ecxL2ConnectionSchemaNames["RedundantUUID"]); ok {
-		if err := conf.ecx.DeleteL2Connection(redID.(string)); err != nil {
-			restErr, ok := err.(rest.Error)
-			if ok {
-				// IC-LAYER2-4021 = Connection already deleted
-				if hasApplicationErrorCode(restErr.ApplicationErrors, "IC-LAYER2-4021") {
-					return diags
-				}
+		if err := conf.ecxClient(ctx).DeleteL2Connection(redID.(string)); err != nil {
+			// IC-LAYER2-4021 = Connection already deleted
+			if hasAPIErrorCode(err, "IC-LAYER2-4021") {
+				return diags
 			}
-			return diag.FromErr(err)
+			return diagFromAPIError(ctx, err, ecxL2ConnectionSchemaNames)
 		}
 		waitConfigs = append(waitConfigs,
-			createConnectionStatusDeleteWaitConfiguration(conf.ecx.GetL2Connection, redID.(string), 2*time.Second, d.Timeout(schema.TimeoutDelete)),
+			createConnectionStatusDeleteWaitConfiguration(conf.ecxClient(ctx).GetL2Connection, redID.(string), 2*time.Second, d.Timeout(schema.TimeoutDelete)),
 		)
 	}
 	for _, config := range waitConfigs {
-		if _, err := config.WaitForStateContext(ctx); err != nil {
+		if _, err := conf.stateWaiter(ctx, "ecx_l2_connection", config).WaitForStateContext(ctx); err != nil {
 			return diag.Errorf("error waiting for connection (%s) to be removed: %s", d.Id(), err)
 		}
 	}
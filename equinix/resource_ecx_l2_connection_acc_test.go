@@ -44,7 +44,7 @@ func testSweepECXL2Connections(region string) error {
 		log.Printf("[INFO][SWEEPER_LOG] error loading configuration: %s", err)
 		return err
 	}
-	conns, err := config.ecx.GetL2OutgoingConnections([]string{
+	conns, err := config.ecxClient(context.Background()).GetL2OutgoingConnections([]string{
 		ecx.ConnectionStatusNotAvailable,
 		ecx.ConnectionStatusPendingAutoApproval,
 		ecx.ConnectionStatusPendingBGPPeering,
@@ -62,7 +62,7 @@ func testSweepECXL2Connections(region string) error {
 			nonSweepableCount++
 			continue
 		}
-		if err := config.ecx.DeleteL2Connection(ecx.StringValue(conn.UUID)); err != nil {
+		if err := config.ecxClient(context.Background()).DeleteL2Connection(ecx.StringValue(conn.UUID)); err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] error deleting ECXL2Connection resource %s (%s): %s", ecx.StringValue(conn.UUID), ecx.StringValue(conn.Name), err)
 		} else {
 			log.Printf("[INFO][SWEEPER_LOG] sent delete request for ECXL2Connection resource %s (%s)", ecx.StringValue(conn.UUID), ecx.StringValue(conn.Name))
@@ -540,7 +540,7 @@ func testAccFabricL2ConnectionExists(resourceName string, conn *ecx.L2Connection
 		if !ok {
 			return fmt.Errorf("resource not found: %s", resourceName)
 		}
-		client := testAccProvider.Meta().(*Config).ecx
+		client := testAccProvider.Meta().(*Config).ecxClient(context.Background())
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource has no ID attribute set")
 		}
@@ -563,7 +563,7 @@ func testAccFabricL2ConnectionSecondaryExists(resourceName string, conn *ecx.L2C
 		if !ok {
 			return fmt.Errorf("resource not found: %s", resourceName)
 		}
-		client := testAccProvider.Meta().(*Config).ecx
+		client := testAccProvider.Meta().(*Config).ecxClient(context.Background())
 
 		if connID, ok := rs.Primary.Attributes["secondary_connection.0.uuid"]; ok {
 			resp, err := client.GetL2Connection(connID)
@@ -51,7 +51,7 @@ func createECXL2ConnectionAccepterResourceSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.StringIsNotEmpty,
 			Description:  ecxL2ConnectionAccepterDescriptions["ConnectionId"],
 		},
-		ecxL2ConnectionAccepterSchemaNames["AccessKey"]: {
+		ecxL2ConnectionAccepterSchemaNames["AccessKey"]: secretSchema(&schema.Schema{
 			Type:         schema.TypeString,
 			Optional:     true,
 			Computed:     true,
@@ -59,8 +59,8 @@ func createECXL2ConnectionAccepterResourceSchema() map[string]*schema.Schema {
 			Sensitive:    true,
 			ValidateFunc: validation.StringIsNotEmpty,
 			Description:  ecxL2ConnectionAccepterDescriptions["AccessKey"],
-		},
-		ecxL2ConnectionAccepterSchemaNames["SecretKey"]: {
+		}),
+		ecxL2ConnectionAccepterSchemaNames["SecretKey"]: secretSchema(&schema.Schema{
 			Type:         schema.TypeString,
 			Optional:     true,
 			Computed:     true,
@@ -68,7 +68,7 @@ func createECXL2ConnectionAccepterResourceSchema() map[string]*schema.Schema {
 			Sensitive:    true,
 			ValidateFunc: validation.StringIsNotEmpty,
 			Description:  ecxL2ConnectionAccepterDescriptions["SecretKey"],
-		},
+		}),
 		ecxL2ConnectionAccepterSchemaNames["Profile"]: {
 			Type:         schema.TypeString,
 			Optional:     true,
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/equinix/ecx-go/v2"
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 )
@@ -376,3 +377,28 @@ func TestFabricL2Connection_fillUpdateRequest(t *testing.T) {
 	assert.Equal(t, changes[ecxL2ConnectionSchemaNames["Speed"]], updateReq.speed, "Update request speed matches")
 	assert.Equal(t, changes[ecxL2ConnectionSchemaNames["SpeedUnit"]], updateReq.speedUnit, "Update speed unit matches")
 }
+
+func TestFabricL2Connection_fakeAPILifecycle(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	config := testFakeAPIConfig(t, api.URL)
+	// when
+	state := testFakeAPILifecycle(t, resourceECXL2Connection(), config, map[string]interface{}{
+		ecxL2ConnectionSchemaNames["Name"]:             "tfacc-connection",
+		ecxL2ConnectionSchemaNames["ProfileUUID"]:      "5d113752-996b-4b59-8e21-8927e7b98058",
+		ecxL2ConnectionSchemaNames["Speed"]:            50,
+		ecxL2ConnectionSchemaNames["SpeedUnit"]:        "MB",
+		ecxL2ConnectionSchemaNames["Notifications"]:    []interface{}{"test@equinix.com"},
+		ecxL2ConnectionSchemaNames["PortUUID"]:         "52c00d7f-c310-458e-9426-1d7549e1f600",
+		ecxL2ConnectionSchemaNames["VlanSTag"]:         1043,
+		ecxL2ConnectionSchemaNames["SellerMetroCode"]:  "SV",
+		ecxL2ConnectionSchemaNames["AuthorizationKey"]: "123456789012",
+	})
+	// then
+	assert.Equal(t, "tfacc-connection", state.Attributes[ecxL2ConnectionSchemaNames["Name"]], "Name is read")
+	assert.Equal(t, ecx.ConnectionStatusProvisioned, state.Attributes[ecxL2ConnectionSchemaNames["Status"]], "Connection is provisioned after create")
+	assert.Equal(t, "1043", state.Attributes[ecxL2ConnectionSchemaNames["VlanSTag"]], "VLAN S-Tag is read")
+	_, exists := api.Object("/ecx/v3/l2/connections/" + state.ID)
+	assert.False(t, exists, "Connection is deprovisioned")
+}
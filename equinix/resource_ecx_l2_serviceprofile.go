@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/equinix/ecx-go/v2"
-	"github.com/equinix/rest-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -344,9 +343,9 @@ func resourceECXL2ServiceProfileCreate(ctx context.Context, d *schema.ResourceDa
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	profile := createECXL2ServiceProfile(d)
-	uuid, err := conf.ecx.CreateL2ServiceProfile(*profile)
+	uuid, err := conf.ecxClient(ctx).CreateL2ServiceProfile(*profile)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ServiceProfileSchemaNames)
 	}
 	d.SetId(ecx.StringValue(uuid))
 	diags = append(diags, resourceECXL2ServiceProfileRead(ctx, d, m)...)
@@ -356,12 +355,12 @@ func resourceECXL2ServiceProfileCreate(ctx context.Context, d *schema.ResourceDa
 func resourceECXL2ServiceProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	profile, err := conf.ecx.GetL2ServiceProfile(d.Id())
+	profile, err := conf.ecxClient(ctx).GetL2ServiceProfile(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ServiceProfileSchemaNames)
 	}
 	if err := updateECXL2ServiceProfileResource(profile, d); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ServiceProfileSchemaNames)
 	}
 	return diags
 }
@@ -370,8 +369,8 @@ func resourceECXL2ServiceProfileUpdate(ctx context.Context, d *schema.ResourceDa
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	profile := createECXL2ServiceProfile(d)
-	if err := conf.ecx.UpdateL2ServiceProfile(*profile); err != nil {
-		return diag.FromErr(err)
+	if err := conf.ecxClient(ctx).UpdateL2ServiceProfile(*profile); err != nil {
+		return diagFromAPIError(ctx, err, ecxL2ServiceProfileSchemaNames)
 	}
 	diags = append(diags, resourceECXL2ServiceProfileRead(ctx, d, m)...)
 	return diags
@@ -380,15 +379,12 @@ func resourceECXL2ServiceProfileUpdate(ctx context.Context, d *schema.ResourceDa
 func resourceECXL2ServiceProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	if err := conf.ecx.DeleteL2ServiceProfile(d.Id()); err != nil {
-		restErr, ok := err.(rest.Error)
-		if ok {
-			// IC-PROFILE-004 =  profile does not exist
-			if hasApplicationErrorCode(restErr.ApplicationErrors, "IC-PROFILE-004") {
-				return diags
-			}
+	if err := conf.ecxClient(ctx).DeleteL2ServiceProfile(d.Id()); err != nil {
+		// IC-PROFILE-004 =  profile does not exist
+		if hasAPIErrorCode(err, "IC-PROFILE-004") {
+			return diags
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, ecxL2ServiceProfileSchemaNames)
 	}
 	return diags
 }
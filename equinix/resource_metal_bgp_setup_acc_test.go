@@ -51,7 +51,7 @@ func TestAccMetalBGPSetup_basic(t *testing.T) {
 }
 
 func testAccMetalBGPSetupCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Config).metal
+	client := testAccProvider.Meta().(*Config).metalClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_bgp_session" {
@@ -1,10 +1,13 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/packethost/packngo"
@@ -54,14 +57,20 @@ func resourceMetalConnection() *schema.Resource {
 	for _, allowedSpeed := range allowedSpeeds {
 		speeds = append(speeds, allowedSpeed.Str)
 	}
-	return &schema.Resource{
-		Read:   resourceMetalConnectionRead,
-		Create: resourceMetalConnectionCreate,
-		Delete: resourceMetalConnectionDelete,
-		Update: resourceMetalConnectionUpdate,
+	return withDeletionProtection(&schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Read:          resourceMetalConnectionRead,
+		CreateContext: metalContextCRUD(resourceMetalConnectionCreate),
+		DeleteContext: metalContextCRUD(resourceMetalConnectionDelete),
+		UpdateContext: metalContextCRUD(resourceMetalConnectionUpdate),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customizeDiffMetalTagsAll,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -138,6 +147,8 @@ func resourceMetalConnection() *schema.Resource {
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"tags_all":            metalTagsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 			"vlans": {
 				Type:        schema.TypeList,
 				Description: "Only used with shared connection. VLANs to attach. Pass one vlan for Primary/Single connection and two vlans for Redundant connection",
@@ -182,11 +193,11 @@ func resourceMetalConnection() *schema.Resource {
 				Elem:        serviceTokenSchema(),
 			},
 		},
-	}
+	}, "equinix_metal_connection", "tags_all")
 }
 
-func resourceMetalConnectionCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalConnectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 
 	facility, facOk := d.GetOk("facility")
 	metro, metOk := d.GetOk("metro")
@@ -221,10 +232,8 @@ func resourceMetalConnectionCreate(d *schema.ResourceData, meta interface{}) err
 		Speed:      speed,
 	}
 
-	// this could be generalized, see $ grep "d.Get(\"tags" *
-	tags := d.Get("tags.#").(int)
-	if tags > 0 {
-		connReq.Tags = convertStringArr(d.Get("tags").([]interface{}))
+	if tags := expandMetalTags(meta, getMetalTags(d, "tags")); len(tags) > 0 {
+		connReq.Tags = tags
 	}
 
 	if metOk {
@@ -284,11 +293,14 @@ func resourceMetalConnectionCreate(d *schema.ResourceData, meta interface{}) err
 		d.SetId(conn.ID)
 	}
 
+	if err := waitForMetalConnectionProvisioned(ctx, d, meta, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
 	return resourceMetalConnectionRead(d, meta)
 }
 
-func resourceMetalConnectionUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 
 	if d.HasChange("locked") {
 		var action func(string) (*packngo.Response, error)
@@ -318,31 +330,23 @@ func resourceMetalConnectionUpdate(d *schema.ResourceData, meta interface{}) err
 		ur.Redundancy = redundancy
 	}
 
-	if d.HasChange("tags") {
-		ts := d.Get("tags")
-		sts := []string{}
-
-		switch ts.(type) {
-		case []interface{}:
-			for _, v := range ts.([]interface{}) {
-				sts = append(sts, v.(string))
-			}
-			ur.Tags = sts
-		default:
-			return friendlyError(fmt.Errorf("garbage in tags: %s", ts))
-		}
+	if d.HasChanges("tags", "tags_all") {
+		ur.Tags = expandMetalTags(meta, getMetalTags(d, "tags"))
 	}
 
 	if !reflect.DeepEqual(ur, packngo.ConnectionUpdateRequest{}) {
 		if _, _, err := client.Connections.Update(d.Id(), &ur, nil); err != nil {
 			return friendlyError(err)
 		}
+		if err := waitForMetalConnectionProvisioned(ctx, d, meta, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
 	}
 	return resourceMetalConnectionRead(d, meta)
 }
 
 func resourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	connId := d.Id()
 
 	conn, _, err := client.Connections.Get(
@@ -394,17 +398,70 @@ func resourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error
 		"speed":              speed,
 		"ports":              getConnectionPorts(conn.Ports),
 		"mode":               mode,
-		"tags":               conn.Tags,
 		"service_tokens":     serviceTokens,
 		"service_token_type": side,
+		"tags": func(d *schema.ResourceData, k string) error {
+			return setMetalTags(d, meta, conn.Tags)
+		},
 	})
 }
 
-func resourceMetalConnectionDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
-	resp, err := client.Connections.Delete(d.Id(), true)
+func resourceMetalConnectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, meta, "equinix_metal_connection", getMetalTags(d, "tags_all")); err != nil {
+		return err
+	}
+	client := meta.(*Config).metalClient()
+	// packngo waits for the deletion at most a minute, the waiter below
+	// honors the configured delete timeout instead
+	resp, err := client.Connections.Delete(d.Id(), false)
 	if ignoreResponseErrors(httpForbidden, httpNotFound)(resp, err) != nil {
 		return friendlyError(err)
 	}
+
+	deleteWaiter := getConnectionStateWaiter(
+		client,
+		d.Id(),
+		d.Timeout(schema.TimeoutDelete),
+		[]string{"deleting"},
+		[]string{},
+	)
+	_, err = meta.(*Config).stateWaiter(ctx, "metal_connection", deleteWaiter).WaitForStateContext(ctx)
+	if ignoreResponseErrors(httpForbidden, httpNotFound)(nil, err) != nil {
+		return fmt.Errorf("Error deleting Metal Connection %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// waitForMetalConnectionProvisioned waits until the connection is not being
+// provisioned. Dedicated connections stay requested until they are approved
+// and shared connections stay pending until their service tokens are used,
+// so neither of these is waited for.
+func waitForMetalConnectionProvisioned(ctx context.Context, d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	waiter := getConnectionStateWaiter(
+		meta.(*Config).metalClient(),
+		d.Id(),
+		timeout,
+		[]string{"provisioning"},
+		[]string{"active", "requested", "pending"},
+	)
+	if _, err := meta.(*Config).stateWaiter(ctx, "metal_connection", waiter).WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("Error waiting for Metal Connection %s to be provisioned: %s", d.Id(), err)
+	}
 	return nil
 }
+
+func getConnectionStateWaiter(client *packngo.Client, id string, timeout time.Duration, pending, target []string) *resource.StateChangeConf {
+	return &resource.StateChangeConf{
+		Pending: pending,
+		Target:  target,
+		Refresh: func() (interface{}, string, error) {
+			conn, _, err := client.Connections.Get(id, nil)
+			if err != nil {
+				return 0, "", err
+			}
+			return conn, conn.Status, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+}
@@ -31,16 +31,16 @@ var (
 )
 
 func resourceMetalDevice() *schema.Resource {
-	return &schema.Resource{
+	return withDeletionProtection(&schema.Resource{
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Update: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
-		Create: resourceMetalDeviceCreate,
-		Read:   resourceMetalDeviceRead,
-		Update: resourceMetalDeviceUpdate,
-		Delete: resourceMetalDeviceDelete,
+		CreateContext: metalContextCRUD(resourceMetalDeviceCreate),
+		Read:          resourceMetalDeviceRead,
+		UpdateContext: metalContextCRUD(resourceMetalDeviceUpdate),
+		DeleteContext: metalContextCRUD(resourceMetalDeviceDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -268,13 +268,13 @@ func resourceMetalDevice() *schema.Resource {
 				Computed:    true,
 			},
 
-			"user_data": {
+			"user_data": secretSchema(&schema.Schema{
 				Type:        schema.TypeString,
 				Description: "A string of the desired User Data for the device",
 				Optional:    true,
 				Sensitive:   true,
 				ForceNew:    false,
-			},
+			}),
 
 			"custom_data": {
 				Type:        schema.TypeString,
@@ -320,6 +320,8 @@ func resourceMetalDevice() *schema.Resource {
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"tags_all":            metalTagsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 			"storage": {
 				Type:        schema.TypeString,
 				Description: "JSON for custom partitioning. Only usable on reserved hardware. More information in in the [Custom Partitioning and RAID](https://metal.equinix.com/developers/docs/servers/custom-partitioning-raid/) doc",
@@ -412,8 +414,15 @@ func resourceMetalDevice() *schema.Resource {
 			customdiff.ForceNewIf("custom_data", shouldReinstall),
 			customdiff.ForceNewIf("operating_system", shouldReinstall),
 			customdiff.ForceNewIf("user_data", shouldReinstall),
+			customizeDiffMetalTagsAll,
 		),
-	}
+	}, "equinix_metal_device", "tags_all", deviceReinstallReplaces)
+}
+
+// deviceReinstallReplaces reports replacement of a device caused by changes
+// of attributes that reinstall the device when reinstall is disabled
+func deviceReinstallReplaces(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+	return (d.HasChange("custom_data") || d.HasChange("operating_system") || d.HasChange("user_data")) && shouldReinstall(ctx, d, meta)
 }
 
 func shouldReinstall(_ context.Context, d *schema.ResourceDiff, meta interface{}) bool {
@@ -442,8 +451,8 @@ func shouldReinstall(_ context.Context, d *schema.ResourceDiff, meta interface{}
 	return !reinstall_config["enabled"].(bool)
 }
 
-func resourceMetalDeviceCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 
 	var addressTypesSlice []packngo.IPAddressCreateRequest
 	_, ok := d.GetOk("ip_address")
@@ -476,8 +485,12 @@ func resourceMetalDeviceCreate(d *schema.ResourceData, meta interface{}) error {
 		createRequest.Metro = metroRaw.(string)
 	}
 
-	if attr, ok := d.GetOk("user_data"); ok {
-		createRequest.UserData = attr.(string)
+	if _, ok := d.GetOk("user_data"); ok {
+		userData, err := getSecret(d, "user_data")
+		if err != nil {
+			return err
+		}
+		createRequest.UserData = userData
 	}
 
 	if attr, ok := d.GetOk("custom_data"); ok {
@@ -540,9 +553,8 @@ func resourceMetalDeviceCreate(d *schema.ResourceData, meta interface{}) error {
 		createRequest.UserSSHKeys = convertStringArr(d.Get("user_ssh_key_ids").([]interface{}))
 	}
 
-	tags := d.Get("tags.#").(int)
-	if tags > 0 {
-		createRequest.Tags = convertStringArr(d.Get("tags").([]interface{}))
+	if tags := expandMetalTags(meta, getMetalTags(d, "tags")); len(tags) > 0 {
+		createRequest.Tags = tags
 	}
 
 	if attr, ok := d.GetOk("storage"); ok {
@@ -569,7 +581,7 @@ func resourceMetalDeviceCreate(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(newDevice.ID)
 
-	if err = waitForActiveDevice(d, meta); err != nil {
+	if err = waitForActiveDevice(ctx, d, meta); err != nil {
 		return err
 	}
 
@@ -577,7 +589,7 @@ func resourceMetalDeviceCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceMetalDeviceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	device, _, err := client.Devices.Get(d.Id(), deviceReadOptions)
 	if err != nil {
@@ -610,7 +622,14 @@ func resourceMetalDeviceRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("updated", device.Updated)
 	d.Set("ipxe_script_url", device.IPXEScriptURL)
 	d.Set("always_pxe", device.AlwaysPXE)
-	d.Set("root_password", device.RootPassword)
+	rootPassword, err := meta.(*Config).stateSecret(d.Get("root_password").(string), device.RootPassword)
+	if err != nil {
+		return err
+	}
+	d.Set("root_password", rootPassword)
+	if err := setSecretsState(d, meta, "user_data"); err != nil {
+		return err
+	}
 	d.Set("project_id", device.Project.ID)
 	if device.Storage != nil {
 		rawStorageBytes, err := json.Marshal(device.Storage)
@@ -644,7 +663,7 @@ func resourceMetalDeviceRead(d *schema.ResourceData, meta interface{}) error {
 		d.Set(tt, nil)
 	}
 
-	d.Set("tags", device.Tags)
+	setMetalTags(d, meta, device.Tags)
 	keyIDs := []string{}
 	for _, k := range device.SSHKeys {
 		keyIDs = append(keyIDs, path.Base(k.URL))
@@ -678,8 +697,8 @@ func resourceMetalDeviceRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceMetalDeviceUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalDeviceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 
 	if d.HasChange("locked") {
 		var action func(string) (*packngo.Response, error)
@@ -699,7 +718,10 @@ func resourceMetalDeviceUpdate(d *schema.ResourceData, meta interface{}) error {
 		ur.Description = &dDesc
 	}
 	if d.HasChange("user_data") {
-		dUserData := d.Get("user_data").(string)
+		dUserData, err := getSecret(d, "user_data")
+		if err != nil {
+			return err
+		}
 		ur.UserData = &dUserData
 	}
 	if d.HasChange("custom_data") {
@@ -710,19 +732,9 @@ func resourceMetalDeviceUpdate(d *schema.ResourceData, meta interface{}) error {
 		dHostname := d.Get("hostname").(string)
 		ur.Hostname = &dHostname
 	}
-	if d.HasChange("tags") {
-		ts := d.Get("tags")
-		sts := []string{}
-
-		switch ts.(type) {
-		case []interface{}:
-			for _, v := range ts.([]interface{}) {
-				sts = append(sts, v.(string))
-			}
-			ur.Tags = &sts
-		default:
-			return friendlyError(fmt.Errorf("garbage in tags: %s", ts))
-		}
+	if d.HasChanges("tags", "tags_all") {
+		sts := expandMetalTags(meta, getMetalTags(d, "tags"))
+		ur.Tags = &sts
 	}
 	if d.HasChange("ipxe_script_url") {
 		dUrl := d.Get("ipxe_script_url").(string)
@@ -748,7 +760,7 @@ func resourceMetalDeviceUpdate(d *schema.ResourceData, meta interface{}) error {
 			return friendlyError(err)
 		}
 
-		if err = waitForActiveDevice(d, meta); err != nil {
+		if err = waitForActiveDevice(ctx, d, meta); err != nil {
 			return err
 		}
 	}
@@ -780,8 +792,11 @@ func getReinstallOptions(d *schema.ResourceData) (packngo.DeviceReinstallFields,
 	}, nil
 }
 
-func resourceMetalDeviceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalDeviceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, meta, "equinix_metal_device", getMetalTags(d, "tags_all")); err != nil {
+		return err
+	}
+	client := meta.(*Config).metalClient()
 
 	fdvIf, fdvOk := d.GetOk("force_detach_volumes")
 	fdv := false
@@ -803,7 +818,7 @@ func resourceMetalDeviceDelete(d *schema.ResourceData, meta interface{}) error {
 			// avoid "context: deadline exceeded"
 			timeout := d.Timeout(schema.TimeoutDelete) - time.Minute - time.Since(start)
 
-			err := waitUntilReservationProvisionable(client, resId.(string), d.Id(), 10*time.Second, timeout, 3*time.Second)
+			err := waitUntilReservationProvisionable(ctx, meta.(*Config), resId.(string), d.Id(), 10*time.Second, timeout, 3*time.Second)
 			if err != nil {
 				return err
 			}
@@ -812,9 +827,9 @@ func resourceMetalDeviceDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func waitForActiveDevice(d *schema.ResourceData, meta interface{}) error {
+func waitForActiveDevice(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	// Wait for the device so we can get the networking attributes that show up after a while.
-	state, err := waitForDeviceAttribute(d, []string{"active", "failed"}, []string{"queued", "provisioning", "reinstalling"}, "state", meta)
+	state, err := waitForDeviceAttribute(ctx, d, []string{"active", "failed"}, []string{"queued", "provisioning", "reinstalling"}, "state", meta)
 	if err != nil {
 		d.SetId("")
 		fErr := friendlyError(err)
@@ -171,11 +171,11 @@ func TestAccMetalDevice_facilityList(t *testing.T) {
 func TestAccMetalDevice_sshConfig(t *testing.T) {
 	rs := acctest.RandString(10)
 	r := "equinix_metal_device.test"
-	userSSHKey, _, err := acctest.RandSSHKeyPair("")
+	userSSHKey, err := testSSHKeyPair(t, "user")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
-	projSSHKey, _, err := acctest.RandSSHKeyPair("")
+	projSSHKey, err := testSSHKeyPair(t, "project")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -398,7 +398,7 @@ func TestAccMetalDevice_IPXEConfigMissing(t *testing.T) {
 }
 
 func testAccMetalDeviceCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Config).metal
+	client := testAccProvider.Meta().(*Config).metalClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_device" {
@@ -434,7 +434,7 @@ func testAccMetalDeviceExists(n string, device *packngo.Device) resource.TestChe
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*Config).metal
+		client := testAccProvider.Meta().(*Config).metalClient()
 
 		foundDevice, _, err := client.Devices.Get(rs.Primary.ID, nil)
 		if err != nil {
@@ -0,0 +1,42 @@
+package equinix
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetalDevice_fakeAPILifecycle(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	defer func(delay time.Duration) { deviceWaitDelay = delay }(deviceWaitDelay)
+	deviceWaitDelay = 0
+	projectID := api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-device"})
+	config := testFakeAPIConfig(t, api.URL)
+	// when
+	state := testFakeAPILifecycle(t, resourceMetalDevice(), config, map[string]interface{}{
+		"project_id":       projectID,
+		"hostname":         "tfacc-device",
+		"plan":             "c3.small.x86",
+		"metro":            "sv",
+		"operating_system": "ubuntu_20_04",
+		"billing_cycle":    "hourly",
+	})
+	// then
+	assert.Equal(t, "tfacc-device", state.Attributes["hostname"], "Hostname is read")
+	assert.Equal(t, "active", state.Attributes["state"], "Device is active after create")
+	assert.Equal(t, "c3.small.x86", state.Attributes["plan"], "Plan is read")
+	_, exists := api.Object("/metal/v1/devices/" + state.ID)
+	assert.False(t, exists, "Device is deleted")
+	creates := 0
+	for _, req := range api.Requests() {
+		if req.Method == http.MethodPost && req.Path == "/metal/v1/projects/"+projectID+"/devices" {
+			creates++
+		}
+	}
+	assert.Equal(t, 1, creates, "Device is created once")
+}
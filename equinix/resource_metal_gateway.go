@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -32,9 +33,12 @@ func intInSlice(valid []int) schema.SchemaValidateFunc {
 
 func resourceMetalGateway() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceMetalGatewayRead,
-		Create: resourceMetalGatewayCreate,
-		Delete: resourceMetalGatewayDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Read:          resourceMetalGatewayRead,
+		Create:        resourceMetalGatewayCreate,
+		DeleteContext: metalContextCRUD(resourceMetalGatewayDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -93,7 +97,7 @@ func resourceMetalGateway() *schema.Resource {
 }
 
 func resourceMetalGatewayCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	_, hasIPReservation := d.GetOk("ip_reservation_id")
 	_, hasSubnetSize := d.GetOk("private_ipv4_subnet_size")
@@ -119,7 +123,7 @@ func resourceMetalGatewayCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceMetalGatewayRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	mgId := d.Id()
 
 	includes := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
@@ -150,8 +154,8 @@ func resourceMetalGatewayRead(d *schema.ResourceData, meta interface{}) error {
 	})
 }
 
-func resourceMetalGatewayDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalGatewayDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	resp, err := client.MetalGateways.Delete(d.Id())
 	if ignoreResponseErrors(httpForbidden, httpNotFound)(resp, err) != nil {
 		return friendlyError(err)
@@ -165,7 +169,7 @@ func resourceMetalGatewayDelete(d *schema.ResourceData, meta interface{}) error
 		[]string{},
 	)
 
-	_, err = deleteWaiter.WaitForState()
+	_, err = meta.(*Config).stateWaiter(ctx, "metal_gateway", deleteWaiter).WaitForStateContext(ctx)
 	if ignoreResponseErrors(httpForbidden, httpNotFound)(nil, err) != nil {
 		return fmt.Errorf("Error deleting Metal Gateway %s: %s", d.Id(), err)
 	}
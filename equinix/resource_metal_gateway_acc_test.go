@@ -96,7 +96,7 @@ resource "equinix_metal_gateway" "test" {
 }
 
 func testAccMetalGatewayCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Config).metal
+	client := testAccProvider.Meta().(*Config).metalClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_gateway" {
@@ -139,7 +139,7 @@ resource "equinix_metal_ip_attachment" "test" {
 }
 
 func testAccMetalIPAttachmentCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Config).metal
+	client := testAccProvider.Meta().(*Config).metalClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_ip_attachment" {
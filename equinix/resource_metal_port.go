@@ -140,7 +140,7 @@ func resourceMetalPortUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceMetalPortRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	port, err := getPortByResourceData(d, client)
 	if err != nil {
 		if isNotFound(err) || isForbidden(err) {
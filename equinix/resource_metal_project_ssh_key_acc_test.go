@@ -48,7 +48,7 @@ resource "equinix_metal_device" "test" {
 func TestAccMetalProjectSSHKey_basic(t *testing.T) {
 	rs := acctest.RandString(10)
 	var key packngo.SSHKey
-	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	publicKeyMaterial, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -80,7 +80,7 @@ func TestAccMetalProjectSSHKey_basic(t *testing.T) {
 }
 
 func testAccMetalProjectSSHKeyCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Config).metal
+	client := testAccProvider.Meta().(*Config).metalClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_project_ssh_key" {
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -165,6 +166,10 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Elem:        &schema.Schema{Type: schema.TypeString},
 	}
 
+	reservedBlockSchema["tags_all"] = metalTagsAllSchema()
+
+	reservedBlockSchema["deletion_protection"] = deletionProtectionSchema()
+
 	reservedBlockSchema["custom_data"] = &schema.Schema{
 		Type:             schema.TypeString,
 		Default:          "{}",
@@ -214,24 +219,25 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Description:  "the size of the network to reserve from an existing vrf ip_range. `cidr` can only be specified with `vrf_id`. Minimum range is 22-29, with 30-31 supported and necessary for virtual-circuits",
 	}
 	// TODO: add comments field, used for reservations that are not automatically approved
-	return &schema.Resource{
-		Create: resourceMetalReservedIPBlockCreate,
-		Read:   resourceMetalReservedIPBlockRead,
-		Update: resourceMetalReservedIPBlockUpdate,
-		Delete: resourceMetalReservedIPBlockDelete,
+	return withDeletionProtection(&schema.Resource{
+		CreateContext: metalContextCRUD(resourceMetalReservedIPBlockCreate),
+		Read:          resourceMetalReservedIPBlockRead,
+		Update:        resourceMetalReservedIPBlockUpdate,
+		Delete:        resourceMetalReservedIPBlockDelete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 
-		Schema: reservedBlockSchema,
+		Schema:        reservedBlockSchema,
+		CustomizeDiff: customizeDiffMetalTagsAll,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(ReservedIPCreateTimeout),
 		},
-	}
+	}, "equinix_metal_reserved_ip_block", "tags_all")
 }
 
-func resourceMetalReservedIPBlockCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	quantity := d.Get("quantity").(int)
 	typ := d.Get("type").(string)
 
@@ -267,10 +273,8 @@ func resourceMetalReservedIPBlockCreate(d *schema.ResourceData, meta interface{}
 		req.Description = desc.(string)
 	}
 
-	if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
-		for _, tag := range tagsRaw.(*schema.Set).List() {
-			req.Tags = append(req.Tags, tag.(string))
-		}
+	if tags := expandMetalTags(meta, getMetalTags(d, "tags")); len(tags) > 0 {
+		req.Tags = tags
 	}
 
 	projectID := d.Get("project_id").(string)
@@ -303,7 +307,7 @@ func resourceMetalReservedIPBlockCreate(d *schema.ResourceData, meta interface{}
 		Timeout:    d.Timeout(schema.TimeoutCreate),
 		MinTimeout: 15 * time.Second,
 	}
-	if _, err := stateConf.WaitForState(); err != nil {
+	if _, err := meta.(*Config).stateWaiter(ctx, "metal_reserved_ip_block", stateConf).WaitForStateContext(ctx); err != nil {
 		return fmt.Errorf("error waiting for IP Reservation (%s) to become %s: %s", d.Id(), wfs, err)
 	}
 
@@ -311,16 +315,11 @@ func resourceMetalReservedIPBlockCreate(d *schema.ResourceData, meta interface{}
 }
 
 func resourceMetalReservedIPBlockUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	id := d.Id()
 	req := &packngo.IPAddressUpdateRequest{}
-	if d.HasChange("tags") {
-		tags := []string{}
-		if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
-			for _, tag := range tagsRaw.(*schema.Set).List() {
-				tags = append(tags, tag.(string))
-			}
-		}
+	if d.HasChanges("tags", "tags_all") {
+		tags := expandMetalTags(meta, getMetalTags(d, "tags"))
 		req.Tags = &tags
 	}
 
@@ -449,7 +448,7 @@ func loadBlock(d *schema.ResourceData, reservedBlock *packngo.IPAddressReservati
 }
 
 func resourceMetalReservedIPBlockRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	id := d.Id()
 
 	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
@@ -469,6 +468,9 @@ func resourceMetalReservedIPBlockRead(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return err
 	}
+	if err := setMetalTags(d, meta, reservedBlock.Tags); err != nil {
+		return err
+	}
 
 	if (reservedBlock.Description != nil) && (*(reservedBlock.Description) != "") {
 		d.Set("description", *(reservedBlock.Description))
@@ -479,7 +481,10 @@ func resourceMetalReservedIPBlockRead(d *schema.ResourceData, meta interface{})
 }
 
 func resourceMetalReservedIPBlockDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	if err := checkDeletionProtection(d, meta, "equinix_metal_reserved_ip_block", getMetalTags(d, "tags_all")); err != nil {
+		return err
+	}
+	client := meta.(*Config).metalClient()
 
 	id := d.Id()
 
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
@@ -13,9 +14,9 @@ import (
 
 func resourceMetalSpotMarketRequest() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalSpotMarketRequestCreate,
-		Read:   resourceMetalSpotMarketRequestRead,
-		Delete: resourceMetalSpotMarketRequestDelete,
+		CreateContext: metalContextCRUD(resourceMetalSpotMarketRequestCreate),
+		Read:          resourceMetalSpotMarketRequestRead,
+		DeleteContext: metalContextCRUD(resourceMetalSpotMarketRequestDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -170,8 +171,8 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 	}
 }
 
-func resourceMetalSpotMarketRequestCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalSpotMarketRequestCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	var waitForDevices bool
 
 	metro := d.Get("metro").(string)
@@ -257,13 +258,8 @@ func resourceMetalSpotMarketRequestCreate(d *schema.ResourceData, meta interface
 		}
 	}
 
-	if val, ok := d.GetOk("instance_parameters.0.tags"); ok {
-		temp := val.([]interface{})
-		for _, i := range temp {
-			if i != nil {
-				params.Tags = append(params.Tags, i.(string))
-			}
-		}
+	if tags := expandMetalTags(meta, getMetalTags(d, "instance_parameters.0.tags")); len(tags) > 0 {
+		params.Tags = tags
 	}
 
 	if val, ok := d.GetOk("instance_parameters.0.user_ssh_keys"); ok {
@@ -302,7 +298,7 @@ func resourceMetalSpotMarketRequestCreate(d *schema.ResourceData, meta interface
 			NotFoundChecks: 600,             // Setting high number, to support long timeouts
 		}
 
-		_, err = stateConf.WaitForState()
+		_, err = meta.(*Config).stateWaiter(ctx, "metal_spot_market_request", stateConf).WaitForStateContext(ctx)
 		if err != nil {
 			return err
 		}
@@ -312,7 +308,7 @@ func resourceMetalSpotMarketRequestCreate(d *schema.ResourceData, meta interface
 }
 
 func resourceMetalSpotMarketRequestRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	smr, _, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
 	if err != nil {
@@ -350,8 +346,8 @@ func resourceMetalSpotMarketRequestRead(d *schema.ResourceData, meta interface{}
 	})
 }
 
-func resourceMetalSpotMarketRequestDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalSpotMarketRequestDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	var waitForDevices bool
 
 	if val, ok := d.GetOk("wait_for_devices"); ok {
@@ -373,7 +369,7 @@ func resourceMetalSpotMarketRequestDelete(d *schema.ResourceData, meta interface
 			NotFoundChecks: 600,             // Setting high number, to support long timeouts
 		}
 
-		_, err = stateConf.WaitForState()
+		_, err = meta.(*Config).stateWaiter(ctx, "metal_spot_market_request", stateConf).WaitForStateContext(ctx)
 		if err != nil {
 			return err
 		}
@@ -416,7 +412,7 @@ func getInstanceParams(params *packngo.SpotMarketRequestInstanceParameters) Inst
 
 func resourceStateRefreshFunc(d *schema.ResourceData, meta interface{}) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		client := meta.(*Config).metal
+		client := meta.(*Config).metalClient()
 		smr, _, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
 		if err != nil {
 			return nil, "", fmt.Errorf("Failed to fetch Spot market request with following error: %s", err.Error())
@@ -49,7 +49,7 @@ func testSweepSSHKeys(region string) error {
 func TestAccMetalSSHKey_basic(t *testing.T) {
 	var key packngo.SSHKey
 	rInt := acctest.RandInt()
-	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	publicKeyMaterial, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -77,7 +77,7 @@ func TestAccMetalSSHKey_basic(t *testing.T) {
 
 func TestAccMetalSSHKey_projectBasic(t *testing.T) {
 	rInt := acctest.RandInt()
-	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	publicKeyMaterial, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -103,7 +103,7 @@ func TestAccMetalSSHKey_projectBasic(t *testing.T) {
 func TestAccMetalSSHKey_update(t *testing.T) {
 	var key packngo.SSHKey
 	rInt := acctest.RandInt()
-	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	publicKeyMaterial, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -138,7 +138,7 @@ func TestAccMetalSSHKey_update(t *testing.T) {
 }
 
 func TestAccMetalSSHKey_projectImportBasic(t *testing.T) {
-	sshKey, _, err := acctest.RandSSHKeyPair("")
+	sshKey, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -160,7 +160,7 @@ func TestAccMetalSSHKey_projectImportBasic(t *testing.T) {
 }
 
 func TestAccMetalSSHKey_importBasic(t *testing.T) {
-	sshKey, _, err := acctest.RandSSHKeyPair("")
+	sshKey, err := testSSHKeyPair(t, "key")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
@@ -183,7 +183,7 @@ func TestAccMetalSSHKey_importBasic(t *testing.T) {
 }
 
 func testAccMetalSSHKeyCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Config).metal
+	client := testAccProvider.Meta().(*Config).metalClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_ssh_key" {
@@ -207,7 +207,7 @@ func testAccCheckMetalSSHKeyExists(n string, key *packngo.SSHKey) resource.TestC
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*Config).metal
+		client := testAccProvider.Meta().(*Config).metalClient()
 
 		foundKey, _, err := client.SSHKeys.Get(rs.Primary.ID, nil)
 		if err != nil {
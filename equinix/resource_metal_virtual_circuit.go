@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -15,13 +16,18 @@ import (
 
 func resourceMetalVirtualCircuit() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceMetalVirtualCircuitRead,
-		Create: resourceMetalVirtualCircuitCreate,
-		Update: resourceMetalVirtualCircuitUpdate,
-		Delete: resourceMetalVirtualCircuitDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Read:          resourceMetalVirtualCircuitRead,
+		CreateContext: metalContextCRUD(resourceMetalVirtualCircuitCreate),
+		Update:        resourceMetalVirtualCircuitUpdate,
+		DeleteContext: metalContextCRUD(resourceMetalVirtualCircuitDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customizeDiffMetalTagsAll,
 
 		Schema: map[string]*schema.Schema{
 			"connection_id": {
@@ -65,6 +71,7 @@ func resourceMetalVirtualCircuit() *schema.Resource {
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"tags_all": metalTagsAllSchema(),
 			"nni_vlan": {
 				Type:        schema.TypeInt,
 				Description: "Equinix Metal network-to-network VLAN ID (optional when the connection has mode=tunnel)",
@@ -138,8 +145,8 @@ func resourceMetalVirtualCircuit() *schema.Resource {
 	}
 }
 
-func resourceMetalVirtualCircuitCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalVirtualCircuitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	vncr := packngo.VCCreateRequest{
 		VirtualNetworkID: d.Get("vlan_id").(string),
 		Name:             d.Get("name").(string),
@@ -157,9 +164,8 @@ func resourceMetalVirtualCircuitCreate(d *schema.ResourceData, meta interface{})
 	portId := d.Get("port_id").(string)
 	projectId := d.Get("project_id").(string)
 
-	tags := d.Get("tags.#").(int)
-	if tags > 0 {
-		vncr.Tags = convertStringArr(d.Get("tags").([]interface{}))
+	if tags := expandMetalTags(meta, getMetalTags(d, "tags")); len(tags) > 0 {
+		vncr.Tags = tags
 	}
 
 	if nniVlan, ok := d.GetOk("nni_vlan"); ok {
@@ -187,7 +193,7 @@ func resourceMetalVirtualCircuitCreate(d *schema.ResourceData, meta interface{})
 		[]string{string(packngo.VCStatusActive)},
 	)
 
-	_, err = createWaiter.WaitForState()
+	_, err = meta.(*Config).stateWaiter(ctx, "metal_virtual_circuit", createWaiter).WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for virtual circuit %s to be created: %s", vc.ID, err.Error())
 	}
@@ -198,7 +204,7 @@ func resourceMetalVirtualCircuitCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceMetalVirtualCircuitRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 	vcId := d.Id()
 
 	vc, _, err := client.VirtualCircuits.Get(
@@ -245,7 +251,9 @@ func resourceMetalVirtualCircuitRead(d *schema.ResourceData, meta interface{}) e
 		"name":        vc.Name,
 		"speed":       strconv.Itoa(vc.Speed),
 		"description": vc.Description,
-		"tags":        vc.Tags,
+		"tags": func(d *schema.ResourceData, k string) error {
+			return setMetalTags(d, meta, vc.Tags)
+		},
 		"peer_asn":    vc.PeerASN,
 		"subnet":      vc.Subnet,
 		"metal_ip":    vc.MetalIP,
@@ -284,7 +292,7 @@ func getVCStateWaiter(client *packngo.Client, id string, timeout time.Duration,
 }
 
 func resourceMetalVirtualCircuitUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	ur := packngo.VCUpdateRequest{}
 	if d.HasChange("vnid") {
@@ -307,19 +315,9 @@ func resourceMetalVirtualCircuitUpdate(d *schema.ResourceData, meta interface{})
 		ur.Speed = speed
 	}
 
-	if d.HasChange("tags") {
-		ts := d.Get("tags")
-		sts := []string{}
-
-		switch ts.(type) {
-		case []interface{}:
-			for _, v := range ts.([]interface{}) {
-				sts = append(sts, v.(string))
-			}
-			ur.Tags = &sts
-		default:
-			return friendlyError(fmt.Errorf("garbage in tags: %s", ts))
-		}
+	if d.HasChanges("tags", "tags_all") {
+		sts := expandMetalTags(meta, getMetalTags(d, "tags"))
+		ur.Tags = &sts
 	}
 
 	if !reflect.DeepEqual(ur, packngo.VCUpdateRequest{}) {
@@ -330,8 +328,8 @@ func resourceMetalVirtualCircuitUpdate(d *schema.ResourceData, meta interface{})
 	return resourceMetalVirtualCircuitRead(d, meta)
 }
 
-func resourceMetalVirtualCircuitDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+func resourceMetalVirtualCircuitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).metalClient()
 	// we first disconnect VLAN from the VC
 	empty := ""
 	_, _, err := client.VirtualCircuits.Update(
@@ -352,7 +350,7 @@ func resourceMetalVirtualCircuitDelete(d *schema.ResourceData, meta interface{})
 		[]string{string(packngo.VCStatusWaiting), string(packngo.VCStatusActive)},
 	)
 
-	_, err = detachWaiter.WaitForState()
+	_, err = meta.(*Config).stateWaiter(ctx, "metal_virtual_circuit", detachWaiter).WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for virtual circuit %s status is not deactivating before deleting it: %s", d.Id(), err)
 	}
@@ -371,7 +369,7 @@ func resourceMetalVirtualCircuitDelete(d *schema.ResourceData, meta interface{})
 		[]string{},
 	)
 
-	_, err = deleteWaiter.WaitForState()
+	_, err = meta.(*Config).stateWaiter(ctx, "metal_virtual_circuit", deleteWaiter).WaitForStateContext(ctx)
 	if ignoreResponseErrors(httpForbidden, httpNotFound)(nil, err) != nil {
 		return fmt.Errorf("Error deleting virtual circuit %s: %s", d.Id(), err)
 	}
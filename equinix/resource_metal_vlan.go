@@ -1,18 +1,30 @@
 package equinix
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"path"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/packethost/packngo"
 )
 
+// VLANs have no tags in packngo, thus provider default_tags and
+// protected_tags don't apply to them and they have no tags_all attribute.
 func resourceMetalVlan() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceMetalVlanCreate,
-		Read:   resourceMetalVlanRead,
-		Delete: resourceMetalVlanDelete,
+	return withDeletionProtection(&schema.Resource{
+		// VLANs are created synchronously, only deletion waits for device
+		// ports to be unassigned
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Create:        resourceMetalVlanCreate,
+		Read:          resourceMetalVlanRead,
+		Update:        resourceMetalVlanUpdate,
+		DeleteContext: metalContextCRUD(resourceMetalVlanDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -69,12 +81,13 @@ func resourceMetalVlan() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
+			"deletion_protection": deletionProtectionSchema(),
 		},
-	}
+	}, "equinix_metal_vlan", "")
 }
 
 func resourceMetalVlanCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	facRaw, facOk := d.GetOk("facility")
 	metroRaw, metroOk := d.GetOk("metro")
@@ -107,7 +120,7 @@ func resourceMetalVlanCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceMetalVlanRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	vlan, _, err := client.ProjectVirtualNetworks.Get(d.Id(),
 		&packngo.GetOptions{Includes: []string{"assigned_to"}})
@@ -128,10 +141,19 @@ func resourceMetalVlanRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceMetalVlanDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+// resourceMetalVlanUpdate stores changes of arguments that are not sent to
+// the API, other VLAN arguments force replacement
+func resourceMetalVlanUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceMetalVlanRead(d, meta)
+}
+
+func resourceMetalVlanDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, meta, "equinix_metal_vlan", nil); err != nil {
+		return err
+	}
+	client := meta.(*Config).metalClient()
 	id := d.Id()
-	vlan, resp, err := client.ProjectVirtualNetworks.Get(id, &packngo.GetOptions{Includes: []string{"instances", "instances.network_ports.virtual_networks", "internet_gateway"}})
+	vlan, resp, err := client.ProjectVirtualNetworks.Get(id, &packngo.GetOptions{Includes: metalVlanPortsIncludes})
 	if ignoreResponseErrors(httpForbidden, httpNotFound)(resp, err) != nil {
 		return friendlyError(err)
 	} else if err != nil {
@@ -140,21 +162,37 @@ func resourceMetalVlanDelete(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// all device ports must be unassigned before delete
-	for _, i := range vlan.Instances {
-		for _, p := range i.NetworkPorts {
-			for _, a := range p.AttachedVirtualNetworks {
-				// a.ID is not set despite including instaces.network_ports.virtual_networks
-				// TODO(displague) packngo should offer GetID() that uses ID or Href
-				aID := path.Base(a.Href)
+	portIDs := metalVlanAssignedPorts(vlan, id)
+	for _, portID := range portIDs {
+		_, resp, err := client.Ports.Unassign(portID, id)
 
-				if aID == id {
-					_, resp, err := client.Ports.Unassign(p.ID, id)
+		if ignoreResponseErrors(httpForbidden, httpNotFound)(resp, err) != nil {
+			return friendlyError(err)
+		}
+	}
 
-					if ignoreResponseErrors(httpForbidden, httpNotFound)(resp, err) != nil {
-						return friendlyError(err)
-					}
+	if len(portIDs) > 0 {
+		unassignWaiter := &resource.StateChangeConf{
+			Pending: []string{"assigned"},
+			Target:  []string{"unassigned"},
+			Refresh: func() (interface{}, string, error) {
+				vlan, _, err := client.ProjectVirtualNetworks.Get(id, &packngo.GetOptions{Includes: metalVlanPortsIncludes})
+				if err != nil {
+					return 0, "", err
 				}
-			}
+				if len(metalVlanAssignedPorts(vlan, id)) > 0 {
+					return vlan, "assigned", nil
+				}
+				return vlan, "unassigned", nil
+			},
+			Timeout:    d.Timeout(schema.TimeoutDelete),
+			MinTimeout: 5 * time.Second,
+		}
+		_, err := meta.(*Config).stateWaiter(ctx, "metal_vlan", unassignWaiter).WaitForStateContext(ctx)
+		if ignoreResponseErrors(httpForbidden, httpNotFound)(nil, err) != nil {
+			return fmt.Errorf("Error unassigning Metal VLAN %s from device ports: %s", id, err)
+		} else if err != nil {
+			return nil
 		}
 	}
 
@@ -162,3 +200,22 @@ func resourceMetalVlanDelete(d *schema.ResourceData, meta interface{}) error {
 
 	return friendlyError(ignoreResponseErrors(httpForbidden, httpNotFound)(client.ProjectVirtualNetworks.Delete(id)))
 }
+
+var metalVlanPortsIncludes = []string{"instances", "instances.network_ports.virtual_networks", "internet_gateway"}
+
+// metalVlanAssignedPorts returns IDs of device ports the VLAN is assigned to
+func metalVlanAssignedPorts(vlan *packngo.VirtualNetwork, id string) []string {
+	var portIDs []string
+	for _, i := range vlan.Instances {
+		for _, p := range i.NetworkPorts {
+			for _, a := range p.AttachedVirtualNetworks {
+				// a.ID is not set despite including instaces.network_ports.virtual_networks
+				// TODO(displague) packngo should offer GetID() that uses ID or Href
+				if path.Base(a.Href) == id {
+					portIDs = append(portIDs, p.ID)
+				}
+			}
+		}
+	}
+	return portIDs
+}
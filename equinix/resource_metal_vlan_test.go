@@ -0,0 +1,109 @@
+package equinix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetalVlan_fakeAPILifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+	api := fakeapi.NewServer()
+	defer api.Close()
+	projectID := api.Seed(fakeapi.MetalProjects, map[string]interface{}{"name": "tfacc-vlan"})
+	config := testFakeAPIProviderConfig(api.URL) + fmt.Sprintf(`
+resource "equinix_metal_vlan" "test" {
+  project_id  = "%s"
+  metro       = "sv"
+  description = "tfacc-vlan"
+}
+`, projectID)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: map[string]*schema.Provider{
+			"equinix": Provider(),
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "equinix_metal_vlan" {
+					continue
+				}
+				if _, ok := api.Object("/metal/v1/virtual-networks/" + rs.Primary.ID); ok {
+					return fmt.Errorf("Metal VLAN %s still exists", rs.Primary.ID)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("equinix_metal_vlan.test", "project_id", projectID),
+					resource.TestCheckResourceAttr("equinix_metal_vlan.test", "metro", "sv"),
+					resource.TestCheckResourceAttr("equinix_metal_vlan.test", "description", "tfacc-vlan"),
+					resource.TestCheckResourceAttr("equinix_metal_vlan.test", "vxlan", "1000"),
+				),
+			},
+		},
+	})
+}
+
+func TestMetalVlan_recordedLifecycle(t *testing.T) {
+	// given
+	config := testRecorderConfig(t, "metal_vlan.json")
+	client := config.metalClient()
+	project, _, err := client.Projects.Create(&packngo.ProjectCreateRequest{Name: "tfacc-recorded-vlan"})
+	if err != nil {
+		t.Fatalf("cannot create project: %s", err)
+	}
+	defer func() {
+		if _, err := client.Projects.Delete(project.ID); err != nil {
+			t.Errorf("cannot delete project: %s", err)
+		}
+	}()
+	// when
+	state := testFakeAPILifecycle(t, resourceMetalVlan(), config, map[string]interface{}{
+		"project_id":  project.ID,
+		"metro":       "sv",
+		"description": "tfacc-recorded-vlan",
+	})
+	// then
+	assert.Equal(t, project.ID, state.Attributes["project_id"])
+	assert.Equal(t, "sv", state.Attributes["metro"])
+	assert.Equal(t, "tfacc-recorded-vlan", state.Attributes["description"])
+	assert.NotEmpty(t, state.Attributes["vxlan"], "VXLAN is assigned")
+}
+
+func TestMetalVlan_assignedPorts(t *testing.T) {
+	// given
+	vlanID := "2c7d4a3e-5b6f-4c8d-9e0f-1a2b3c4d5e6f"
+	vlan := &packngo.VirtualNetwork{
+		Instances: []*packngo.Device{
+			{NetworkPorts: []packngo.Port{
+				{ID: "bond0", AttachedVirtualNetworks: []packngo.VirtualNetwork{
+					{Href: "/metal/v1/virtual-networks/" + vlanID},
+				}},
+				{ID: "eth1", AttachedVirtualNetworks: []packngo.VirtualNetwork{
+					{Href: "/metal/v1/virtual-networks/other"},
+				}},
+			}},
+			{NetworkPorts: []packngo.Port{
+				{ID: "bond1", AttachedVirtualNetworks: []packngo.VirtualNetwork{
+					{Href: "/metal/v1/virtual-networks/other"},
+					{Href: "/metal/v1/virtual-networks/" + vlanID},
+				}},
+			}},
+		},
+	}
+	// when
+	portIDs := metalVlanAssignedPorts(vlan, vlanID)
+	// then
+	assert.Equal(t, []string{"bond0", "bond1"}, portIDs, "Only ports with the VLAN are returned")
+	assert.Empty(t, metalVlanAssignedPorts(&packngo.VirtualNetwork{}, vlanID), "VLAN without devices has no ports")
+}
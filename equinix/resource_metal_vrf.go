@@ -62,7 +62,7 @@ func resourceMetalVRF() *schema.Resource {
 }
 
 func resourceMetalVRFCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	createRequest := &packngo.VRFCreateRequest{
 		Name:        d.Get("name").(string),
@@ -84,7 +84,7 @@ func resourceMetalVRFCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceMetalVRFUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	sPtr := func(s string) *string { return &s }
 	iPtr := func(i int) *int { return &i }
@@ -113,7 +113,7 @@ func resourceMetalVRFUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceMetalVRFRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	getOpts := &packngo.GetOptions{Includes: []string{"project", "metro"}}
 
@@ -140,7 +140,7 @@ func resourceMetalVRFRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceMetalVRFDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*Config).metal
+	client := meta.(*Config).metalClient()
 
 	resp, err := client.VRFs.Delete(d.Id())
 	if ignoreResponseErrors(httpForbidden, httpNotFound)(resp, err) == nil {
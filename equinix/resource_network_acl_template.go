@@ -227,9 +227,9 @@ func resourceNetworkACLTemplateCreate(ctx context.Context, d *schema.ResourceDat
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	template := createACLTemplate(d)
-	uuid, err := conf.ne.CreateACLTemplate(template)
+	uuid, err := conf.neClient(ctx).CreateACLTemplate(template)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkACLTemplateSchemaNames)
 	}
 	d.SetId(ne.StringValue(uuid))
 	diags = append(diags, resourceNetworkACLTemplateRead(ctx, d, m)...)
@@ -239,7 +239,7 @@ func resourceNetworkACLTemplateCreate(ctx context.Context, d *schema.ResourceDat
 func resourceNetworkACLTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	template, err := conf.ne.GetACLTemplate(d.Id())
+	template, err := conf.neClient(ctx).GetACLTemplate(d.Id())
 	if err != nil {
 		if restErr, ok := err.(rest.Error); ok {
 			if restErr.HTTPCode == http.StatusNotFound {
@@ -247,10 +247,10 @@ func resourceNetworkACLTemplateRead(ctx context.Context, d *schema.ResourceData,
 				return diags
 			}
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkACLTemplateSchemaNames)
 	}
 	if err := updateACLTemplateResource(template, d); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkACLTemplateSchemaNames)
 	}
 	return diags
 }
@@ -259,8 +259,8 @@ func resourceNetworkACLTemplateUpdate(ctx context.Context, d *schema.ResourceDat
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	template := createACLTemplate(d)
-	if err := conf.ne.ReplaceACLTemplate(d.Id(), template); err != nil {
-		return diag.FromErr(err)
+	if err := conf.neClient(ctx).ReplaceACLTemplate(d.Id(), template); err != nil {
+		return diagFromAPIError(ctx, err, networkACLTemplateSchemaNames)
 	}
 	diags = append(diags, resourceNetworkACLTemplateRead(ctx, d, m)...)
 	return diags
@@ -270,12 +270,12 @@ func resourceNetworkACLTemplateDelete(ctx context.Context, d *schema.ResourceDat
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	if devID, ok := d.GetOk(networkACLTemplateSchemaNames["DeviceUUID"]); ok {
-		if err := conf.ne.NewDeviceUpdateRequest(devID.(string)).WithACLTemplate("").Execute(); err != nil {
+		if err := conf.neClient(ctx).NewDeviceUpdateRequest(devID.(string)).WithACLTemplate("").Execute(); err != nil {
 			log.Printf("[WARN] could not unassign ACL template %q from device %q: %s", d.Id(), devID, err)
 		}
 	}
-	if err := conf.ne.DeleteACLTemplate(d.Id()); err != nil {
-		return diag.FromErr(err)
+	if err := conf.neClient(ctx).DeleteACLTemplate(d.Id()); err != nil {
+		return diagFromAPIError(ctx, err, networkACLTemplateSchemaNames)
 	}
 	return diags
 }
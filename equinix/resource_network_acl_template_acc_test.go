@@ -27,7 +27,7 @@ func testSweepNetworkACLTemplate(region string) error {
 		log.Printf("[INFO][SWEEPER_LOG] error loading configuration: %s", err)
 		return err
 	}
-	templates, err := config.ne.GetACLTemplates()
+	templates, err := config.neClient(context.Background()).GetACLTemplates()
 	if err != nil {
 		log.Printf("[INFO][SWEEPER_LOG] error fetching Network ACL Templates list: %s", err)
 		return err
@@ -38,7 +38,7 @@ func testSweepNetworkACLTemplate(region string) error {
 			nonSweepableCount++
 			continue
 		}
-		if err := config.ne.DeleteACLTemplate(ne.StringValue(template.UUID)); err != nil {
+		if err := config.neClient(context.Background()).DeleteACLTemplate(ne.StringValue(template.UUID)); err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] error deleting NetworkACLTemplate resource %s (%s): %s", ne.StringValue(template.UUID), ne.StringValue(template.Name), err)
 		} else {
 			log.Printf("[INFO][SWEEPER_LOG] sent delete request for NetworkACLTemplate resource %s (%s)", ne.StringValue(template.UUID), ne.StringValue(template.Name))
@@ -143,7 +143,7 @@ func testAccNetworkACLTemplateExists(resourceName string, template *ne.ACLTempla
 		if !ok {
 			return fmt.Errorf("resource not found: %s", resourceName)
 		}
-		client := testAccProvider.Meta().(*Config).ne
+		client := testAccProvider.Meta().(*Config).neClient(context.Background())
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource has no ID attribute set")
 		}
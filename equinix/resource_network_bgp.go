@@ -100,13 +100,13 @@ func createNetworkBGPResourceSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.IntAtLeast(1),
 			Description:  networkBGPDescriptions["RemoteASN"],
 		},
-		networkBGPSchemaNames["AuthenticationKey"]: {
+		networkBGPSchemaNames["AuthenticationKey"]: secretSchema(&schema.Schema{
 			Type:         schema.TypeString,
 			Optional:     true,
 			Sensitive:    true,
 			ValidateFunc: validation.StringLenBetween(6, 60),
 			Description:  networkBGPDescriptions["AuthenticationKey"],
-		},
+		}),
 		networkBGPSchemaNames["State"]: {
 			Type:        schema.TypeString,
 			Computed:    true,
@@ -124,10 +124,13 @@ func resourceNetworkBGPCreate(ctx context.Context, d *schema.ResourceData, m int
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	bgp := createNetworkBGPConfiguration(d)
-	existingBGP, err := conf.ne.GetBGPConfigurationForConnection(ne.StringValue(bgp.ConnectionUUID))
+	if err := resolveNetworkBGPAuthenticationKey(d, &bgp); err != nil {
+		return diag.FromErr(err)
+	}
+	existingBGP, err := conf.neClient(ctx).GetBGPConfigurationForConnection(ne.StringValue(bgp.ConnectionUUID))
 	if err == nil {
 		bgp.UUID = existingBGP.UUID
-		if updateErr := createNetworkBGPUpdateRequest(conf.ne.NewBGPConfigurationUpdateRequest, &bgp); updateErr != nil {
+		if updateErr := createNetworkBGPUpdateRequest(conf.neClient(ctx).NewBGPConfigurationUpdateRequest, &bgp); updateErr != nil {
 			return diag.Errorf("failed to update BGP configuration '%s': %s", ne.StringValue(existingBGP.UUID), updateErr)
 		}
 		d.SetId(ne.StringValue(bgp.UUID))
@@ -136,13 +139,13 @@ func resourceNetworkBGPCreate(ctx context.Context, d *schema.ResourceData, m int
 		if !ok || restErr.HTTPCode != http.StatusNotFound {
 			return diag.Errorf("failed to fetch BGP configuration for connection '%s': %s", ne.StringValue(bgp.ConnectionUUID), err)
 		}
-		uuid, err := conf.ne.CreateBGPConfiguration(bgp)
+		uuid, err := conf.neClient(ctx).CreateBGPConfiguration(bgp)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromAPIError(ctx, err, networkBGPSchemaNames)
 		}
 		d.SetId(ne.StringValue(uuid))
 	}
-	if _, err := createBGPConfigStatusProvisioningWaitConfiguration(conf.ne.GetBGPConfiguration, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutCreate)).WaitForStateContext(ctx); err != nil {
+	if _, err := conf.stateWaiter(ctx, "network_bgp", createBGPConfigStatusProvisioningWaitConfiguration(conf.neClient(ctx).GetBGPConfiguration, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutCreate))).WaitForStateContext(ctx); err != nil {
 		return diag.Errorf("error waiting for BGP configuration (%s) to be created: %s", d.Id(), err)
 	}
 	diags = append(diags, resourceNetworkBGPRead(ctx, d, m)...)
@@ -152,12 +155,19 @@ func resourceNetworkBGPCreate(ctx context.Context, d *schema.ResourceData, m int
 func resourceNetworkBGPRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	bgp, err := conf.ne.GetBGPConfiguration(d.Id())
+	bgp, err := conf.neClient(ctx).GetBGPConfiguration(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkBGPSchemaNames)
+	}
+	if bgp.AuthenticationKey != nil {
+		authKey, err := conf.stateSecret(d.Get(networkBGPSchemaNames["AuthenticationKey"]).(string), ne.StringValue(bgp.AuthenticationKey))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		bgp.AuthenticationKey = ne.String(authKey)
 	}
 	if err := updateNetworkBGPResource(bgp, d); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkBGPSchemaNames)
 	}
 	return diags
 }
@@ -166,9 +176,12 @@ func resourceNetworkBGPUpdate(ctx context.Context, d *schema.ResourceData, m int
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	bgpConfig := createNetworkBGPConfiguration(d)
-	if err := createNetworkBGPUpdateRequest(conf.ne.NewBGPConfigurationUpdateRequest, &bgpConfig).Execute(); err != nil {
+	if err := resolveNetworkBGPAuthenticationKey(d, &bgpConfig); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := createNetworkBGPUpdateRequest(conf.neClient(ctx).NewBGPConfigurationUpdateRequest, &bgpConfig).Execute(); err != nil {
+		return diagFromAPIError(ctx, err, networkBGPSchemaNames)
+	}
 	diags = append(diags, resourceNetworkBGPRead(ctx, d, m)...)
 	return diags
 }
@@ -205,6 +218,20 @@ func createNetworkBGPConfiguration(d *schema.ResourceData) ne.BGPConfiguration {
 	return bgp
 }
 
+// resolveNetworkBGPAuthenticationKey sets authentication key of a given
+// BGP configuration to the secret value sent to API
+func resolveNetworkBGPAuthenticationKey(d *schema.ResourceData, bgp *ne.BGPConfiguration) error {
+	if bgp.AuthenticationKey == nil {
+		return nil
+	}
+	authKey, err := getSecret(d, networkBGPSchemaNames["AuthenticationKey"])
+	if err != nil {
+		return err
+	}
+	bgp.AuthenticationKey = ne.String(authKey)
+	return nil
+}
+
 func updateNetworkBGPResource(bgp *ne.BGPConfiguration, d *schema.ResourceData) error {
 	if err := d.Set(networkBGPSchemaNames["UUID"], bgp.UUID); err != nil {
 		return fmt.Errorf("error reading UUID: %s", err)
@@ -2,10 +2,12 @@ package equinix
 
 import (
 	"context"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/equinix/ne-go"
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 )
@@ -143,3 +145,31 @@ func TestNetworkBGP_statusProvisioningWaitConfiguration(t *testing.T) {
 	assert.Equal(t, timeout, waitConfig.Timeout, "Device status wait configuration timeout matches")
 	assert.Equal(t, delay, waitConfig.MinTimeout, "Device status wait configuration min timeout matches")
 }
+
+func TestNetworkBGP_readSecretReference(t *testing.T) {
+	// given
+	os.Setenv("TF_EQUINIX_TEST_SECRET", "authKey")
+	defer os.Unsetenv("TF_EQUINIX_TEST_SECRET")
+	api := fakeapi.NewServer()
+	defer api.Close()
+	id := api.Seed(fakeapi.NetworkBGPConfigurations, map[string]interface{}{
+		"connectionUuid":     "6ca8d0df-c71a-4475-a835-53c2df1e6667",
+		"localIpAddress":     "1.1.1.1/32",
+		"localAsn":           15344,
+		"remoteIpAddress":    "2.2.2.2",
+		"remoteAsn":          60421,
+		"authenticationKey":  "authKey",
+		"provisioningStatus": "PROVISIONED",
+	})
+	config := testFakeAPIConfig(t, api.URL)
+	d := schema.TestResourceDataRaw(t, createNetworkBGPResourceSchema(), map[string]interface{}{
+		networkBGPSchemaNames["AuthenticationKey"]: "env://TF_EQUINIX_TEST_SECRET",
+	})
+	d.SetId(id)
+	// when
+	diags := resourceNetworkBGPRead(context.Background(), d, config)
+	// then
+	assert.False(t, diags.HasError(), "Error is not returned")
+	assert.Equal(t, "env://TF_EQUINIX_TEST_SECRET", d.Get(networkBGPSchemaNames["AuthenticationKey"]), "Reference to secret is kept in state")
+	assert.Equal(t, "1.1.1.1/32", d.Get(networkBGPSchemaNames["LocalIPAddress"]), "BGP configuration is read")
+}
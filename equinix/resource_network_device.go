@@ -59,6 +59,7 @@ var neDeviceSchemaNames = map[string]string{
 	"Secondary":           "secondary_device",
 	"ClusterDetails":      "cluster_details",
 	"ValidStatusList":     "valid_status_list",
+	"DeletionProtection":  "deletion_protection",
 }
 
 var neDeviceDescriptions = map[string]string{
@@ -188,7 +189,7 @@ var neDeviceVendorConfigDescriptions = map[string]string{
 }
 
 func resourceNetworkDevice() *schema.Resource {
-	return &schema.Resource{
+	return withDeletionProtection(&schema.Resource{
 		CreateContext: resourceNetworkDeviceCreate,
 		ReadContext:   resourceNetworkDeviceRead,
 		UpdateContext: resourceNetworkDeviceUpdate,
@@ -203,7 +204,7 @@ func resourceNetworkDevice() *schema.Resource {
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 		Description: "Resource allows creation and management of Equinix Network Edge virtual devices",
-	}
+	}, "equinix_network_device", "")
 }
 
 func createNetworkDeviceSchema() map[string]*schema.Schema {
@@ -302,14 +303,14 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 			ForceNew:    true,
 			Description: neDeviceDescriptions["IsBYOL"],
 		},
-		neDeviceSchemaNames["LicenseToken"]: {
+		neDeviceSchemaNames["LicenseToken"]: secretSchema(&schema.Schema{
 			Type:          schema.TypeString,
 			Optional:      true,
 			ForceNew:      true,
 			ValidateFunc:  validation.StringIsNotEmpty,
 			ConflictsWith: []string{neDeviceSchemaNames["LicenseFile"]},
 			Description:   neDeviceDescriptions["LicenseToken"],
-		},
+		}),
 		neDeviceSchemaNames["LicenseFile"]: {
 			Type:         schema.TypeString,
 			Optional:     true,
@@ -518,14 +519,14 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 						ForceNew:    true,
 						Description: neDeviceDescriptions["HostName"],
 					},
-					neDeviceSchemaNames["LicenseToken"]: {
+					neDeviceSchemaNames["LicenseToken"]: secretSchema(&schema.Schema{
 						Type:          schema.TypeString,
 						Optional:      true,
 						ForceNew:      true,
 						ValidateFunc:  validation.StringIsNotEmpty,
 						ConflictsWith: []string{neDeviceSchemaNames["Secondary"] + ".0." + neDeviceSchemaNames["LicenseFile"]},
 						Description:   neDeviceDescriptions["LicenseToken"],
-					},
+					}),
 					neDeviceSchemaNames["LicenseFile"]: {
 						Type:         schema.TypeString,
 						Optional:     true,
@@ -643,6 +644,7 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 				},
 			},
 		},
+		neDeviceSchemaNames["DeletionProtection"]: deletionProtectionSchema(),
 		neDeviceSchemaNames["ClusterDetails"]: {
 			Type:        schema.TypeList,
 			Optional:    true,
@@ -763,14 +765,14 @@ func createClusterNodeDetailSchema() map[string]*schema.Schema {
 			ConflictsWith: []string{neDeviceSchemaNames["LicenseFileID"]},
 			Description:   neDeviceClusterNodeDescriptions["LicenseFileId"],
 		},
-		neDeviceClusterNodeSchemaNames["LicenseToken"]: {
+		neDeviceClusterNodeSchemaNames["LicenseToken"]: secretSchema(&schema.Schema{
 			Type:          schema.TypeString,
 			Optional:      true,
 			ForceNew:      true,
 			Sensitive:     true,
 			ConflictsWith: []string{neDeviceSchemaNames["LicenseToken"]},
 			Description:   neDeviceClusterNodeDescriptions["LicenseToken"],
-		},
+		}),
 		neDeviceClusterNodeSchemaNames["VendorConfiguration"]: {
 			Type:     schema.TypeList,
 			Optional: true,
@@ -845,38 +847,41 @@ func resourceNetworkDeviceCreate(ctx context.Context, d *schema.ResourceData, m
 	var diags diag.Diagnostics
 	primary, secondary := createNetworkDevices(d)
 	var err error
-	if err := uploadDeviceLicenseFile(os.Open, conf.ne.UploadLicenseFile, ne.StringValue(primary.TypeCode), primary); err != nil {
+	if err := resolveNetworkDeviceLicenseTokens(primary, secondary); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := uploadDeviceLicenseFile(os.Open, conf.neClient(ctx).UploadLicenseFile, ne.StringValue(primary.TypeCode), primary); err != nil {
 		return diag.Errorf("could not upload primary device license file due to %s", err)
 	}
-	if err := uploadDeviceLicenseFile(os.Open, conf.ne.UploadLicenseFile, ne.StringValue(primary.TypeCode), secondary); err != nil {
+	if err := uploadDeviceLicenseFile(os.Open, conf.neClient(ctx).UploadLicenseFile, ne.StringValue(primary.TypeCode), secondary); err != nil {
 		return diag.Errorf("could not upload secondary device license file due to %s", err)
 	}
 	if secondary != nil {
-		primary.UUID, secondary.UUID, err = conf.ne.CreateRedundantDevice(*primary, *secondary)
+		primary.UUID, secondary.UUID, err = conf.neClient(ctx).CreateRedundantDevice(*primary, *secondary)
 	} else {
-		primary.UUID, err = conf.ne.CreateDevice(*primary)
+		primary.UUID, err = conf.neClient(ctx).CreateDevice(*primary)
 	}
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, neDeviceSchemaNames)
 	}
 	d.SetId(ne.StringValue(primary.UUID))
 	waitConfigs := []*resource.StateChangeConf{
-		createNetworkDeviceStatusProvisioningWaitConfiguration(conf.ne.GetDevice, ne.StringValue(primary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
-		createNetworkDeviceLicenseStatusWaitConfiguration(conf.ne.GetDevice, ne.StringValue(primary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
+		createNetworkDeviceStatusProvisioningWaitConfiguration(conf.neClient(ctx).GetDevice, ne.StringValue(primary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
+		createNetworkDeviceLicenseStatusWaitConfiguration(conf.neClient(ctx).GetDevice, ne.StringValue(primary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
 	}
 	if ne.StringValue(primary.ACLTemplateUUID) != "" || ne.StringValue(primary.MgmtAclTemplateUuid) != "" {
 		waitConfigs = append(waitConfigs,
-			createNetworkDeviceACLStatusWaitConfiguration(conf.ne.GetDeviceACLDetails, ne.StringValue(primary.UUID), 1*time.Second, d.Timeout(schema.TimeoutUpdate)),
+			createNetworkDeviceACLStatusWaitConfiguration(conf.neClient(ctx).GetDeviceACLDetails, ne.StringValue(primary.UUID), 1*time.Second, d.Timeout(schema.TimeoutUpdate)),
 		)
 	}
 	if secondary != nil {
 		waitConfigs = append(waitConfigs,
-			createNetworkDeviceStatusProvisioningWaitConfiguration(conf.ne.GetDevice, ne.StringValue(secondary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
-			createNetworkDeviceLicenseStatusWaitConfiguration(conf.ne.GetDevice, ne.StringValue(secondary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
+			createNetworkDeviceStatusProvisioningWaitConfiguration(conf.neClient(ctx).GetDevice, ne.StringValue(secondary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
+			createNetworkDeviceLicenseStatusWaitConfiguration(conf.neClient(ctx).GetDevice, ne.StringValue(secondary.UUID), 5*time.Second, d.Timeout(schema.TimeoutCreate)),
 		)
 		if ne.StringValue(secondary.ACLTemplateUUID) != "" || ne.StringValue(secondary.MgmtAclTemplateUuid) != "" {
 			waitConfigs = append(waitConfigs,
-				createNetworkDeviceACLStatusWaitConfiguration(conf.ne.GetDeviceACLDetails, ne.StringValue(secondary.UUID), 1*time.Second, d.Timeout(schema.TimeoutUpdate)),
+				createNetworkDeviceACLStatusWaitConfiguration(conf.neClient(ctx).GetDeviceACLDetails, ne.StringValue(secondary.UUID), 1*time.Second, d.Timeout(schema.TimeoutUpdate)),
 			)
 		}
 	}
@@ -884,7 +889,7 @@ func resourceNetworkDeviceCreate(ctx context.Context, d *schema.ResourceData, m
 		if config == nil {
 			continue
 		}
-		if _, err := config.WaitForStateContext(ctx); err != nil {
+		if _, err := conf.stateWaiter(ctx, "network_device", config).WaitForStateContext(ctx); err != nil {
 			return diag.Errorf("error waiting for network device (%s) to be created: %s", ne.StringValue(primary.UUID), err)
 		}
 	}
@@ -897,7 +902,7 @@ func resourceNetworkDeviceRead(ctx context.Context, d *schema.ResourceData, m in
 	var diags diag.Diagnostics
 	var err error
 	var primary, secondary *ne.Device
-	primary, err = conf.ne.GetDevice(d.Id())
+	primary, err = conf.neClient(ctx).GetDevice(d.Id())
 	if err != nil {
 		return diag.Errorf("cannot fetch primary network device due to %v", err)
 	}
@@ -906,12 +911,18 @@ func resourceNetworkDeviceRead(ctx context.Context, d *schema.ResourceData, m in
 		return diags
 	}
 	if ne.StringValue(primary.RedundantUUID) != "" {
-		secondary, err = conf.ne.GetDevice(ne.StringValue(primary.RedundantUUID))
+		secondary, err = conf.neClient(ctx).GetDevice(ne.StringValue(primary.RedundantUUID))
 		if err != nil {
 			return diag.Errorf("cannot fetch secondary network device due to %v", err)
 		}
 	}
 	if err = updateNetworkDeviceResource(primary, secondary, d); err != nil {
+		return diagFromAPIError(ctx, err, neDeviceSchemaNames)
+	}
+	if err := setSecretsState(d, m, neDeviceSchemaNames["LicenseToken"]); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := setNetworkDeviceClusterLicenseTokensState(d, conf); err != nil {
 		return diag.FromErr(err)
 	}
 	return diags
@@ -925,26 +936,26 @@ func resourceNetworkDeviceUpdate(ctx context.Context, d *schema.ResourceData, m
 		neDeviceSchemaNames["Notifications"], neDeviceSchemaNames["AdditionalBandwidth"],
 		neDeviceSchemaNames["ACLTemplateUUID"], neDeviceSchemaNames["MgmtAclTemplateUuid"],
 	}
-	updateReq := conf.ne.NewDeviceUpdateRequest(d.Id())
+	updateReq := conf.neClient(ctx).NewDeviceUpdateRequest(d.Id())
 	primaryChanges := getResourceDataChangedKeys(supportedChanges, d)
 	if err := fillNetworkDeviceUpdateRequest(updateReq, primaryChanges).Execute(); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, neDeviceSchemaNames)
 	}
 	var secondaryChanges map[string]interface{}
 	if v, ok := d.GetOk(neDeviceSchemaNames["RedundantUUID"]); ok {
 		secondaryChanges = getResourceDataListElementChanges(supportedChanges, neDeviceSchemaNames["Secondary"], 0, d)
-		secondaryUpdateReq := conf.ne.NewDeviceUpdateRequest(v.(string))
+		secondaryUpdateReq := conf.neClient(ctx).NewDeviceUpdateRequest(v.(string))
 		if err := fillNetworkDeviceUpdateRequest(secondaryUpdateReq, secondaryChanges).Execute(); err != nil {
-			return diag.FromErr(err)
+			return diagFromAPIError(ctx, err, neDeviceSchemaNames)
 		}
 	}
-	for _, stateChangeConf := range getNetworkDeviceStateChangeConfigs(conf.ne, d.Id(), d.Timeout(schema.TimeoutUpdate), primaryChanges) {
-		if _, err := stateChangeConf.WaitForStateContext(ctx); err != nil {
+	for _, stateChangeConf := range getNetworkDeviceStateChangeConfigs(conf.neClient(ctx), d.Id(), d.Timeout(schema.TimeoutUpdate), primaryChanges) {
+		if _, err := conf.stateWaiter(ctx, "network_device", stateChangeConf).WaitForStateContext(ctx); err != nil {
 			return diag.Errorf("error waiting for network device %q to be updated: %s", d.Id(), err)
 		}
 	}
-	for _, stateChangeConf := range getNetworkDeviceStateChangeConfigs(conf.ne, d.Get(neDeviceSchemaNames["RedundantUUID"]).(string), d.Timeout(schema.TimeoutUpdate), secondaryChanges) {
-		if _, err := stateChangeConf.WaitForStateContext(ctx); err != nil {
+	for _, stateChangeConf := range getNetworkDeviceStateChangeConfigs(conf.neClient(ctx), d.Get(neDeviceSchemaNames["RedundantUUID"]).(string), d.Timeout(schema.TimeoutUpdate), secondaryChanges) {
+		if _, err := conf.stateWaiter(ctx, "network_device", stateChangeConf).WaitForStateContext(ctx); err != nil {
 			return diag.Errorf("error waiting for network device %q to be updated: %s", d.Get(neDeviceSchemaNames["RedundantUUID"]), err)
 		}
 	}
@@ -955,17 +966,20 @@ func resourceNetworkDeviceUpdate(ctx context.Context, d *schema.ResourceData, m
 func resourceNetworkDeviceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
+	if err := checkDeletionProtection(d, m, "equinix_network_device", nil); err != nil {
+		return diag.FromErr(err)
+	}
 	waitConfigs := []*resource.StateChangeConf{
-		createNetworkDeviceStatusDeleteWaitConfiguration(conf.ne.GetDevice, d.Id(), 5*time.Second, d.Timeout(schema.TimeoutDelete)),
+		createNetworkDeviceStatusDeleteWaitConfiguration(conf.neClient(ctx).GetDevice, d.Id(), 5*time.Second, d.Timeout(schema.TimeoutDelete)),
 	}
 	if v, ok := d.GetOk(neDeviceSchemaNames["Secondary"]); ok {
 		if secondary := expandNetworkDeviceSecondary(v.([]interface{})); secondary != nil {
 			waitConfigs = append(waitConfigs,
-				createNetworkDeviceStatusDeleteWaitConfiguration(conf.ne.GetDevice, ne.StringValue(secondary.UUID), 5*time.Second, d.Timeout(schema.TimeoutDelete)),
+				createNetworkDeviceStatusDeleteWaitConfiguration(conf.neClient(ctx).GetDevice, ne.StringValue(secondary.UUID), 5*time.Second, d.Timeout(schema.TimeoutDelete)),
 			)
 		}
 	}
-	if err := conf.ne.DeleteDevice(d.Id()); err != nil {
+	if err := conf.neClient(ctx).DeleteDevice(d.Id()); err != nil {
 		if restErr, ok := err.(rest.Error); ok {
 			for _, detailedErr := range restErr.ApplicationErrors {
 				if detailedErr.Code == ne.ErrorCodeDeviceRemoved {
@@ -973,16 +987,79 @@ func resourceNetworkDeviceDelete(ctx context.Context, d *schema.ResourceData, m
 				}
 			}
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, neDeviceSchemaNames)
 	}
 	for _, config := range waitConfigs {
-		if _, err := config.WaitForStateContext(ctx); err != nil {
+		if _, err := conf.stateWaiter(ctx, "network_device", config).WaitForStateContext(ctx); err != nil {
 			return diag.Errorf("error waiting for network device (%s) to be removed: %s", d.Id(), err)
 		}
 	}
 	return diags
 }
 
+// resolveNetworkDeviceLicenseTokens replaces license tokens of given devices
+// and their cluster nodes with values read from environment variables or files
+func resolveNetworkDeviceLicenseTokens(devices ...*ne.Device) error {
+	for _, device := range devices {
+		if device == nil {
+			continue
+		}
+		tokens := []**string{&device.LicenseToken}
+		if device.ClusterDetails != nil {
+			for _, node := range []*ne.ClusterNodeDetail{device.ClusterDetails.Node0, device.ClusterDetails.Node1} {
+				if node != nil {
+					tokens = append(tokens, &node.LicenseToken)
+				}
+			}
+		}
+		for _, token := range tokens {
+			if *token == nil {
+				continue
+			}
+			value, err := resolveSecret(**token)
+			if err != nil {
+				return err
+			}
+			*token = ne.String(value)
+		}
+	}
+	return nil
+}
+
+// setNetworkDeviceClusterLicenseTokensState replaces configured license tokens
+// of cluster nodes in state with their hashes, when secrets hashing is enabled
+func setNetworkDeviceClusterLicenseTokensState(d *schema.ResourceData, conf *Config) error {
+	v, ok := d.GetOk(neDeviceSchemaNames["ClusterDetails"])
+	if !ok || !conf.HashSecrets {
+		return nil
+	}
+	clusterDetails := v.([]interface{})
+	cluster, ok := clusterDetails[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, nodeName := range []string{neDeviceClusterSchemaNames["Node0"], neDeviceClusterSchemaNames["Node1"]} {
+		nodes, ok := cluster[nodeName].([]interface{})
+		if !ok || len(nodes) < 1 {
+			continue
+		}
+		node, ok := nodes[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		token, _ := node[neDeviceClusterNodeSchemaNames["LicenseToken"]].(string)
+		hashed, err := conf.stateConfigSecret(token)
+		if err != nil {
+			return fmt.Errorf("error reading ClusterDetails: %s", err)
+		}
+		node[neDeviceClusterNodeSchemaNames["LicenseToken"]] = hashed
+	}
+	if err := d.Set(neDeviceSchemaNames["ClusterDetails"], clusterDetails); err != nil {
+		return fmt.Errorf("error reading ClusterDetails: %s", err)
+	}
+	return nil
+}
+
 func createNetworkDevices(d *schema.ResourceData) (*ne.Device, *ne.Device) {
 	var primary, secondary *ne.Device
 	primary = &ne.Device{}
@@ -223,12 +223,12 @@ func resourceNetworkDeviceLinkCreate(ctx context.Context, d *schema.ResourceData
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	link := createNetworkDeviceLink(d)
-	uuid, err := conf.ne.CreateDeviceLinkGroup(link)
+	uuid, err := conf.neClient(ctx).CreateDeviceLinkGroup(link)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkDeviceLinkSchemaNames)
 	}
 	d.SetId(ne.StringValue(uuid))
-	if _, err := createDeviceLinkStatusProvisioningWaitConfiguration(conf.ne.GetDeviceLinkGroup, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutCreate)).WaitForStateContext(ctx); err != nil {
+	if _, err := conf.stateWaiter(ctx, "network_device_link", createDeviceLinkStatusProvisioningWaitConfiguration(conf.neClient(ctx).GetDeviceLinkGroup, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutCreate))).WaitForStateContext(ctx); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity:      diag.Error,
 			Summary:       "Failed to wait for device link to become provisioned",
@@ -243,7 +243,7 @@ func resourceNetworkDeviceLinkCreate(ctx context.Context, d *schema.ResourceData
 func resourceNetworkDeviceLinkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	link, err := conf.ne.GetDeviceLinkGroup(d.Id())
+	link, err := conf.neClient(ctx).GetDeviceLinkGroup(d.Id())
 	if err != nil {
 		if isRestNotFoundError(err) {
 			d.SetId("")
@@ -251,14 +251,14 @@ func resourceNetworkDeviceLinkRead(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 	for i, linkDevice := range link.Devices {
-		device, err := conf.ne.GetDevice(ne.StringValue(linkDevice.DeviceID))
+		device, err := conf.neClient(ctx).GetDevice(ne.StringValue(linkDevice.DeviceID))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromAPIError(ctx, err, networkDeviceLinkSchemaNames)
 		}
 		link.Devices[i].ASN = device.ASN
 	}
 	if err := updateNetworkDeviceLinkResource(link, d); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkDeviceLinkSchemaNames)
 	}
 	return diags
 }
@@ -270,7 +270,7 @@ func resourceNetworkDeviceLinkUpdate(ctx context.Context, d *schema.ResourceData
 		networkDeviceLinkSchemaNames["Name"], networkDeviceLinkSchemaNames["Subnet"],
 		networkDeviceLinkSchemaNames["Devices"], networkDeviceLinkSchemaNames["Links"],
 	}, d)
-	updateReq := conf.ne.NewDeviceLinkGroupUpdateRequest(d.Id())
+	updateReq := conf.neClient(ctx).NewDeviceLinkGroupUpdateRequest(d.Id())
 	for change, changeValue := range changes {
 		switch change {
 		case networkDeviceLinkSchemaNames["Name"]:
@@ -286,9 +286,9 @@ func resourceNetworkDeviceLinkUpdate(ctx context.Context, d *schema.ResourceData
 		}
 	}
 	if err := updateReq.Execute(); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkDeviceLinkSchemaNames)
 	}
-	if _, err := createDeviceLinkStatusProvisioningWaitConfiguration(conf.ne.GetDeviceLinkGroup, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutCreate)).WaitForStateContext(ctx); err != nil {
+	if _, err := conf.stateWaiter(ctx, "network_device_link", createDeviceLinkStatusProvisioningWaitConfiguration(conf.neClient(ctx).GetDeviceLinkGroup, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutCreate))).WaitForStateContext(ctx); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity:      diag.Error,
 			Summary:       "Failed to wait for device link to become provisioned",
@@ -303,13 +303,13 @@ func resourceNetworkDeviceLinkUpdate(ctx context.Context, d *schema.ResourceData
 func resourceNetworkDeviceLinkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	if err := conf.ne.DeleteDeviceLinkGroup(d.Id()); err != nil {
+	if err := conf.neClient(ctx).DeleteDeviceLinkGroup(d.Id()); err != nil {
 		if isRestNotFoundError(err) {
 			return nil
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkDeviceLinkSchemaNames)
 	}
-	if _, err := createDeviceLinkStatusDeleteWaitConfiguration(conf.ne.GetDeviceLinkGroup, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutDelete)).WaitForStateContext(ctx); err != nil {
+	if _, err := conf.stateWaiter(ctx, "network_device_link", createDeviceLinkStatusDeleteWaitConfiguration(conf.neClient(ctx).GetDeviceLinkGroup, d.Id(), 2*time.Second, d.Timeout(schema.TimeoutDelete))).WaitForStateContext(ctx); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity:      diag.Error,
 			Summary:       "Failed to wait for device link to become deprovisioned",
@@ -28,7 +28,7 @@ func testSweepNetworkDeviceLink(region string) error {
 		log.Printf("[INFO][SWEEPER_LOG] error loading configuration: %s", err)
 		return err
 	}
-	links, err := config.ne.GetDeviceLinkGroups()
+	links, err := config.neClient(context.Background()).GetDeviceLinkGroups()
 	if err != nil {
 		log.Printf("[INFO][SWEEPER_LOG] error fetching device links list: %s", err)
 		return err
@@ -39,7 +39,7 @@ func testSweepNetworkDeviceLink(region string) error {
 			nonSweepableCount++
 			continue
 		}
-		if err := config.ne.DeleteDeviceLinkGroup(ne.StringValue(link.UUID)); err != nil {
+		if err := config.neClient(context.Background()).DeleteDeviceLinkGroup(ne.StringValue(link.UUID)); err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] error deleting NetworkDeviceLink resource %s (%s): %s", ne.StringValue(link.UUID), ne.StringValue(link.Name), err)
 		} else {
 			log.Printf("[INFO][SWEEPER_LOG] sent delete request for NetworkDeviceLink resource %s (%s)", ne.StringValue(link.UUID), ne.StringValue(link.Name))
@@ -157,7 +157,7 @@ func testAccNeDeviceLinkExists(resourceName string, deviceLink *ne.DeviceLinkGro
 		if !ok {
 			return fmt.Errorf("resource not found: %s", resourceName)
 		}
-		client := testAccProvider.Meta().(*Config).ne
+		client := testAccProvider.Meta().(*Config).neClient(context.Background())
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource has no ID attribute set")
 		}
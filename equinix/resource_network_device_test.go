@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/equinix/ne-go"
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/fakeapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 )
@@ -434,3 +435,28 @@ func TestNetworkDevice_AdditionalBandwidthStatusWaitConfiguration(t *testing.T)
 	assert.Equal(t, timeout, waitConfig.Timeout, "Additional bandwidth status wait configuration timeout matches")
 	assert.Equal(t, delay, waitConfig.MinTimeout, "Additional bandwidth wait configuration min timeout matches")
 }
+
+func TestNetworkDevice_fakeAPILifecycle(t *testing.T) {
+	// given
+	api := fakeapi.NewServer()
+	defer api.Close()
+	config := testFakeAPIConfig(t, api.URL)
+	// when
+	state := testFakeAPILifecycle(t, resourceNetworkDevice(), config, map[string]interface{}{
+		neDeviceSchemaNames["Name"]:          "tfacc-device",
+		neDeviceSchemaNames["MetroCode"]:     "SV",
+		neDeviceSchemaNames["TypeCode"]:      "CSR1000V",
+		neDeviceSchemaNames["PackageCode"]:   "SEC",
+		neDeviceSchemaNames["Notifications"]: []interface{}{"test@equinix.com"},
+		neDeviceSchemaNames["TermLength"]:    1,
+		neDeviceSchemaNames["AccountNumber"]: "123456",
+		neDeviceSchemaNames["Version"]:       "16.09.05",
+		neDeviceSchemaNames["CoreCount"]:     2,
+	})
+	// then
+	assert.Equal(t, "tfacc-device", state.Attributes[neDeviceSchemaNames["Name"]], "Name is read")
+	assert.Equal(t, ne.DeviceStateProvisioned, state.Attributes[neDeviceSchemaNames["Status"]], "Device is provisioned after create")
+	assert.Equal(t, ne.DeviceLicenseStateRegistered, state.Attributes[neDeviceSchemaNames["LicenseStatus"]], "License is registered after create")
+	_, exists := api.Object("/ne/v1/devices/" + state.ID)
+	assert.False(t, exists, "Device is deprovisioned")
+}
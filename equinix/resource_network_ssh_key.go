@@ -69,9 +69,9 @@ func resourceNetworkSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m
 	conf := m.(*Config)
 	var diags diag.Diagnostics
 	key := createNetworkSSHKey(d)
-	uuid, err := conf.ne.CreateSSHPublicKey(key)
+	uuid, err := conf.neClient(ctx).CreateSSHPublicKey(key)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkSSHKeySchemaNames)
 	}
 	d.SetId(ne.StringValue(uuid))
 	diags = append(diags, resourceNetworkSSHKeyRead(ctx, d, m)...)
@@ -81,7 +81,7 @@ func resourceNetworkSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m
 func resourceNetworkSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	key, err := conf.ne.GetSSHPublicKey(d.Id())
+	key, err := conf.neClient(ctx).GetSSHPublicKey(d.Id())
 	if err != nil {
 		if restErr, ok := err.(rest.Error); ok {
 			if restErr.HTTPCode == http.StatusNotFound {
@@ -89,10 +89,10 @@ func resourceNetworkSSHKeyRead(ctx context.Context, d *schema.ResourceData, m in
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkSSHKeySchemaNames)
 	}
 	if err := updateNetworkSSHKeyResource(key, d); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkSSHKeySchemaNames)
 	}
 	return diags
 }
@@ -100,7 +100,7 @@ func resourceNetworkSSHKeyRead(ctx context.Context, d *schema.ResourceData, m in
 func resourceNetworkSSHKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	if err := conf.ne.DeleteSSHPublicKey(d.Id()); err != nil {
+	if err := conf.neClient(ctx).DeleteSSHPublicKey(d.Id()); err != nil {
 		if restErr, ok := err.(rest.Error); ok {
 			for _, detailedErr := range restErr.ApplicationErrors {
 				if detailedErr.Code == ne.ErrorCodeSSHPublicKeyInvalid {
@@ -108,7 +108,7 @@ func resourceNetworkSSHKeyDelete(ctx context.Context, d *schema.ResourceData, m
 				}
 			}
 		}
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkSSHKeySchemaNames)
 	}
 	return diags
 }
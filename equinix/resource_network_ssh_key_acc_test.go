@@ -27,7 +27,7 @@ func testSweepNetworkSSHKey(region string) error {
 		log.Printf("[INFO][SWEEPER_LOG] error loading configuration: %s", err)
 		return err
 	}
-	keys, err := config.ne.GetSSHPublicKeys()
+	keys, err := config.neClient(context.Background()).GetSSHPublicKeys()
 	if err != nil {
 		log.Printf("[INFO][SWEEPER_LOG] error fetching NetworkSSHKey list: %s", err)
 		return err
@@ -38,7 +38,7 @@ func testSweepNetworkSSHKey(region string) error {
 			nonSweepableCount++
 			continue
 		}
-		if err := config.ne.DeleteSSHPublicKey(ne.StringValue(key.UUID)); err != nil {
+		if err := config.neClient(context.Background()).DeleteSSHPublicKey(ne.StringValue(key.UUID)); err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] error deleting NetworkSSHKey resource %s (%s): %s", ne.StringValue(key.UUID), ne.StringValue(key.Name), err)
 		} else {
 			log.Printf("[INFO][SWEEPER_LOG] sent delete request for NetworkSSHKey resource %s (%s)", ne.StringValue(key.UUID), ne.StringValue(key.Name))
@@ -94,7 +94,7 @@ func testAccNetworkSSHKeyExists(resourceName string, key *ne.SSHPublicKey) resou
 		if !ok {
 			return fmt.Errorf("resource not found: %s", resourceName)
 		}
-		client := testAccProvider.Meta().(*Config).ne
+		client := testAccProvider.Meta().(*Config).neClient(context.Background())
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource has no ID attribute set")
 		}
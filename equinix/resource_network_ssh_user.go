@@ -53,13 +53,13 @@ func createNetworkSSHUserResourceSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.StringLenBetween(3, 32),
 			Description:  networkSSHUserDescriptions["Username"],
 		},
-		networkSSHUserSchemaNames["Password"]: {
+		networkSSHUserSchemaNames["Password"]: secretSchema(&schema.Schema{
 			Type:         schema.TypeString,
 			Sensitive:    true,
 			Required:     true,
 			ValidateFunc: validation.StringLenBetween(8, 20),
 			Description:  networkSSHUserDescriptions["Password"],
-		},
+		}),
 		networkSSHUserSchemaNames["DeviceUUIDs"]: {
 			Type:     schema.TypeSet,
 			Required: true,
@@ -80,12 +80,16 @@ func resourceNetworkSSHUserCreate(ctx context.Context, d *schema.ResourceData, m
 	if len(user.DeviceUUIDs) < 0 {
 		return diag.Errorf("create ssh-user failed: user needs to have at least one device defined")
 	}
-	uuid, err := conf.ne.CreateSSHUser(ne.StringValue(user.Username), ne.StringValue(user.Password), user.DeviceUUIDs[0])
+	password, err := getSecret(d, networkSSHUserSchemaNames["Password"])
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	uuid, err := conf.neClient(ctx).CreateSSHUser(ne.StringValue(user.Username), password, user.DeviceUUIDs[0])
+	if err != nil {
+		return diagFromAPIError(ctx, err, networkSSHUserSchemaNames)
+	}
 	d.SetId(ne.StringValue(uuid))
-	userUpdateReq := conf.ne.NewSSHUserUpdateRequest(ne.StringValue(uuid))
+	userUpdateReq := conf.neClient(ctx).NewSSHUserUpdateRequest(ne.StringValue(uuid))
 	userUpdateReq.WithDeviceChange([]string{}, user.DeviceUUIDs[1:len(user.DeviceUUIDs)])
 	if err := userUpdateReq.Execute(); err != nil {
 		diags = append(diags, diag.Diagnostic{
@@ -102,11 +106,21 @@ func resourceNetworkSSHUserCreate(ctx context.Context, d *schema.ResourceData, m
 func resourceNetworkSSHUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	user, err := conf.ne.GetSSHUser(d.Id())
+	user, err := conf.neClient(ctx).GetSSHUser(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkSSHUserSchemaNames)
+	}
+	if user.Password != nil {
+		password, err := conf.stateSecret(d.Get(networkSSHUserSchemaNames["Password"]).(string), ne.StringValue(user.Password))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		user.Password = ne.String(password)
 	}
 	if err := updateNetworkSSHUserResource(user, d); err != nil {
+		return diagFromAPIError(ctx, err, networkSSHUserSchemaNames)
+	}
+	if err := setSecretsState(d, m, networkSSHUserSchemaNames["Password"]); err != nil {
 		return diag.FromErr(err)
 	}
 	return diags
@@ -115,9 +129,13 @@ func resourceNetworkSSHUserRead(ctx context.Context, d *schema.ResourceData, m i
 func resourceNetworkSSHUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	updateReq := conf.ne.NewSSHUserUpdateRequest(d.Id())
-	if v, ok := d.GetOk(networkSSHUserSchemaNames["Password"]); ok && d.HasChange(networkSSHUserSchemaNames["Password"]) {
-		updateReq.WithNewPassword(v.(string))
+	updateReq := conf.neClient(ctx).NewSSHUserUpdateRequest(d.Id())
+	if _, ok := d.GetOk(networkSSHUserSchemaNames["Password"]); ok && d.HasChange(networkSSHUserSchemaNames["Password"]) {
+		password, err := getSecret(d, networkSSHUserSchemaNames["Password"])
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		updateReq.WithNewPassword(password)
 	}
 	if d.HasChange(networkSSHUserSchemaNames["DeviceUUIDs"]) {
 		a, b := d.GetChange(networkSSHUserSchemaNames["DeviceUUIDs"])
@@ -126,7 +144,7 @@ func resourceNetworkSSHUserUpdate(ctx context.Context, d *schema.ResourceData, m
 		updateReq.WithDeviceChange(aList, bList)
 	}
 	if err := updateReq.Execute(); err != nil {
-		return diag.FromErr(err)
+		return diagFromAPIError(ctx, err, networkSSHUserSchemaNames)
 	}
 	diags = append(diags, resourceNetworkSSHUserRead(ctx, d, m)...)
 	return diags
@@ -135,8 +153,8 @@ func resourceNetworkSSHUserUpdate(ctx context.Context, d *schema.ResourceData, m
 func resourceNetworkSSHUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conf := m.(*Config)
 	var diags diag.Diagnostics
-	if err := conf.ne.DeleteSSHUser(d.Id()); err != nil {
-		return diag.FromErr(err)
+	if err := conf.neClient(ctx).DeleteSSHUser(d.Id()); err != nil {
+		return diagFromAPIError(ctx, err, networkSSHUserSchemaNames)
 	}
 	return diags
 }
@@ -29,7 +29,7 @@ func testSweepNetworkSSHUser(region string) error {
 		log.Printf("[INFO][SWEEPER_LOG] error loading configuration: %s", err)
 		return err
 	}
-	users, err := config.ne.GetSSHUsers()
+	users, err := config.neClient(context.Background()).GetSSHUsers()
 	if err != nil {
 		log.Printf("[INFO][SWEEPER_LOG] error fetching NetworkSSHUser list: %s", err)
 		return err
@@ -38,7 +38,7 @@ func testSweepNetworkSSHUser(region string) error {
 		if !isSweepableTestResource(ne.StringValue(user.Username)) {
 			continue
 		}
-		if err := config.ne.DeleteSSHUser(ne.StringValue(user.UUID)); err != nil {
+		if err := config.neClient(context.Background()).DeleteSSHUser(ne.StringValue(user.UUID)); err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] error deleting NetworkSSHUser resource %s (%s): %s", ne.StringValue(user.UUID), ne.StringValue(user.Username), err)
 		} else {
 			log.Printf("[INFO][SWEEPER_LOG] sent delete request for NetworkSSHUser resource %s (%s)", ne.StringValue(user.UUID), ne.StringValue(user.Username))
@@ -78,7 +78,7 @@ func testAccNeSSHUserExists(resourceName string, user *ne.SSHUser) resource.Test
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource has no ID attribute set")
 		}
-		client := testAccProvider.Meta().(*Config).ne
+		client := testAccProvider.Meta().(*Config).neClient(context.Background())
 		resp, err := client.GetSSHUser(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error when fetching SSH user '%s': %s", rs.Primary.ID, err)
@@ -0,0 +1,159 @@
+package equinix
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+var redirectsErrorRe = regexp.MustCompile(`stopped after \d+ redirects\z`)
+
+// retryableStatusCodes are response codes of idempotent requests that are
+// retried
+var retryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type requestMethodCtxKey struct{}
+
+// requestMethodTransport passes request method to retry policy,
+// as retry policy is not given request when there is no response
+type requestMethodTransport struct {
+	next http.RoundTripper
+}
+
+func (t *requestMethodTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := context.WithValue(req.Context(), requestMethodCtxKey{}, req.Method)
+	return t.next.RoundTrip(req.WithContext(ctx))
+}
+
+// newRetryableHTTPClient returns HTTP client of a given service that retries
+// failed requests with RetryPolicy and RetryBackoff, according to retry
+// settings. Timeout is applied to every single attempt, zero means no timeout.
+func (c *Config) newRetryableHTTPClient(service string, transport http.RoundTripper, timeout time.Duration) *http.Client {
+	retryClient := retryablehttp.NewClient()
+	retryClient.HTTPClient.Transport = transport
+	retryClient.HTTPClient.Timeout = timeout
+	retryClient.RetryMax = c.MaxRetries
+	retryClient.RetryWaitMin = time.Second
+	retryClient.RetryWaitMax = c.MaxRetryWait
+	retryClient.CheckRetry = RetryPolicy
+	retryClient.Backoff = RetryBackoff
+	retryClient.RequestLogHook = countRetries(service)
+	// API errors of last attempt are returned to the clients for parsing
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	standardClient := retryClient.StandardClient()
+	standardClient.Transport = &requestMethodTransport{standardClient.Transport}
+	return standardClient
+}
+
+// RetryPolicy is a retry policy shared by Equinix Metal, Fabric and Network Edge
+// clients. Idempotent requests are retried on recoverable connection errors
+// and on throttling or unavailability responses. Non idempotent requests are
+// retried only when they were rejected by rate limiting.
+func RetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	method, _ := ctx.Value(requestMethodCtxKey{}).(string)
+	if resp != nil && resp.Request != nil {
+		method = resp.Request.Method
+	}
+
+	if err != nil {
+		// Don't retry requests rejected in read only mode.
+		var readOnlyErr *ReadOnlyError
+		if errors.As(err, &readOnlyErr) {
+			return false, nil
+		}
+
+		if v, ok := err.(*url.Error); ok {
+			// Don't retry if the error was due to too many redirects.
+			if redirectsErrorRe.MatchString(v.Error()) {
+				return false, nil
+			}
+
+			// Don't retry if the error was due to TLS cert verification failure.
+			if _, ok := v.Err.(x509.UnknownAuthorityError); ok {
+				return false, nil
+			}
+		}
+
+		// The error is likely recoverable so retry, unless request could
+		// have been already processed.
+		return isIdempotentMethod(method), nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true, nil
+	}
+	for _, code := range retryableStatusCodes {
+		if resp.StatusCode == code {
+			return isIdempotentMethod(method), nil
+		}
+	}
+	return false, nil
+}
+
+// RetryBackoff honors Retry-After header of throttling and unavailability
+// responses and falls back to exponential backoff with jitter. Wait time
+// is always limited to max.
+func RetryBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > max {
+				return max
+			}
+			return wait
+		}
+	}
+	backoff := float64(min) * math.Pow(2, float64(attemptNum))
+	if backoff > float64(max) || math.IsInf(backoff, 0) {
+		backoff = float64(max)
+	}
+	if backoff <= float64(min) {
+		return min
+	}
+	// full jitter between min and exponential backoff
+	return min + time.Duration(rand.Int63n(int64(backoff)-int64(min)))
+}
+
+// parseRetryAfter parses Retry-After header given either as
+// delay seconds or HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	// given
+	response := func(method string, code int) *http.Response {
+		return &http.Response{
+			StatusCode: code,
+			Request:    &http.Request{Method: method},
+		}
+	}
+	postCtx := context.WithValue(context.Background(), requestMethodCtxKey{}, http.MethodPost)
+	getCtx := context.WithValue(context.Background(), requestMethodCtxKey{}, http.MethodGet)
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{"GET on connection error", getCtx, nil, errors.New("connection reset"), true},
+		{"POST on connection error", postCtx, nil, errors.New("connection reset"), false},
+		{"GET on 503", getCtx, response(http.MethodGet, http.StatusServiceUnavailable), nil, true},
+		{"DELETE on 502", getCtx, response(http.MethodDelete, http.StatusBadGateway), nil, true},
+		{"POST on 503", postCtx, response(http.MethodPost, http.StatusServiceUnavailable), nil, false},
+		{"POST on 429", postCtx, response(http.MethodPost, http.StatusTooManyRequests), nil, true},
+		{"GET on 404", getCtx, response(http.MethodGet, http.StatusNotFound), nil, false},
+		{"GET on 200", getCtx, response(http.MethodGet, http.StatusOK), nil, false},
+	}
+	for _, tc := range tests {
+		// when
+		result, err := RetryPolicy(tc.ctx, tc.resp, tc.err)
+		// then
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expected, result, tc.name)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	// given
+	min := time.Second
+	max := 30 * time.Second
+	throttled := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"7"}},
+	}
+	throttledLong := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"120"}},
+	}
+	// when then
+	assert.Equal(t, 7*time.Second, RetryBackoff(min, max, 1, throttled), "Retry-After header is honored")
+	assert.Equal(t, max, RetryBackoff(min, max, 1, throttledLong), "Retry-After is limited to max wait")
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := RetryBackoff(min, max, attempt, nil)
+		assert.GreaterOrEqual(t, int64(wait), int64(min), "Backoff is not shorter than min wait")
+		assert.LessOrEqual(t, int64(wait), int64(max), "Backoff is not longer than max wait")
+	}
+}
+
+func TestRetryBackoff_parseRetryAfter(t *testing.T) {
+	// given
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	// when
+	seconds, secondsOk := parseRetryAfter("10")
+	untilDate, dateOk := parseRetryAfter(date)
+	_, invalidOk := parseRetryAfter("soon")
+	// then
+	assert.True(t, secondsOk)
+	assert.Equal(t, 10*time.Second, seconds)
+	assert.True(t, dateOk)
+	assert.InDelta(t, float64(time.Minute), float64(untilDate), float64(2*time.Second))
+	assert.False(t, invalidOk)
+}
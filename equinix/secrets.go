@@ -0,0 +1,219 @@
+package equinix
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// secretEnvPrefix marks value of a secret attribute read from environment
+	// variable, i.e. env://LICENSE_TOKEN
+	secretEnvPrefix = "env://"
+	// secretFilePrefix marks value of a secret attribute read from a file,
+	// i.e. file:///run/secrets/license_token
+	secretFilePrefix = "file://"
+	// secretHashPrefix marks salted hash of a secret stored in state instead
+	// of its value
+	secretHashPrefix     = "pbkdf2-sha256:"
+	secretHashIterations = 10000
+	secretHashSaltLength = 16
+	secretHashKeyLength  = 32
+)
+
+// isSecretReference returns true when a given value of secret attribute
+// points to environment variable or file with the secret
+func isSecretReference(value string) bool {
+	return strings.HasPrefix(value, secretEnvPrefix) || strings.HasPrefix(value, secretFilePrefix)
+}
+
+// resolveSecret returns value of a secret attribute. Values in form of
+// env://NAME and file://PATH are read from environment variable or file,
+// other values are returned as they are.
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s with secret value is not set", name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, secretFilePrefix):
+		path := strings.TrimPrefix(value, secretFilePrefix)
+		secret, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("cannot read secret value from file %s: %s", path, err)
+		}
+		return strings.TrimRight(string(secret), "\r\n"), nil
+	}
+	return value, nil
+}
+
+// hashSecret returns salted hash of a given secret, in form of
+// pbkdf2-sha256:<iterations>:<salt>:<key>
+func hashSecret(value string) (string, error) {
+	salt := make([]byte, secretHashSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("cannot generate salt of secret hash: %s", err)
+	}
+	key := pbkdf2.Key([]byte(value), salt, secretHashIterations, secretHashKeyLength, sha256.New)
+	return fmt.Sprintf("%s%d:%s:%s", secretHashPrefix, secretHashIterations, hex.EncodeToString(salt), hex.EncodeToString(key)), nil
+}
+
+// parseSecretHash returns iterations, salt and key of a given secret hash
+func parseSecretHash(hash string) (int, []byte, []byte, bool) {
+	if !strings.HasPrefix(hash, secretHashPrefix) {
+		return 0, nil, nil, false
+	}
+	parts := strings.Split(strings.TrimPrefix(hash, secretHashPrefix), ":")
+	if len(parts) != 3 {
+		return 0, nil, nil, false
+	}
+	iterations, err := strconv.Atoi(parts[0])
+	if err != nil || iterations < 1 {
+		return 0, nil, nil, false
+	}
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, nil, false
+	}
+	key, err := hex.DecodeString(parts[2])
+	if err != nil || len(key) == 0 {
+		return 0, nil, nil, false
+	}
+	return iterations, salt, key, true
+}
+
+// isHashedSecret returns true when a given value is a secret hash
+func isHashedSecret(value string) bool {
+	_, _, _, ok := parseSecretHash(value)
+	return ok
+}
+
+// secretMatchesHash returns true when a given secret has a given hash
+func secretMatchesHash(value, hash string) bool {
+	iterations, salt, key, ok := parseSecretHash(hash)
+	if !ok {
+		return false
+	}
+	return hmac.Equal(key, pbkdf2.Key([]byte(value), salt, iterations, len(key), sha256.New))
+}
+
+// secretSchema marks a given string attribute as secret. Secret values can
+// be read from environment variables or files and, when secrets hashing is
+// enabled, are stored in state as salted hashes, which are compared with
+// configured values on plan.
+func secretSchema(s *schema.Schema) *schema.Schema {
+	s.Sensitive = true
+	if validate := s.ValidateFunc; validate != nil {
+		s.ValidateFunc = func(i interface{}, k string) ([]string, []error) {
+			if v, ok := i.(string); ok && isSecretReference(v) {
+				return nil, nil
+			}
+			return validate(i, k)
+		}
+	}
+	suppress := s.DiffSuppressFunc
+	s.DiffSuppressFunc = func(k, old, new string, d *schema.ResourceData) bool {
+		if suppressHashedSecretDiff(k, old, new, d) {
+			return true
+		}
+		return suppress != nil && suppress(k, old, new, d)
+	}
+	return s
+}
+
+// suppressHashedSecretDiff suppresses diff of a secret stored in state as
+// hash when configured value has the same hash
+func suppressHashedSecretDiff(k, old, new string, d *schema.ResourceData) bool {
+	if new == "" || !isHashedSecret(old) {
+		return false
+	}
+	value, err := resolveSecret(new)
+	if err != nil {
+		return false
+	}
+	return secretMatchesHash(value, old)
+}
+
+// stateSecret returns value of a secret received from API to be stored in
+// state. With secrets hashing enabled that is salted hash of the value,
+// unless current state already holds hash of the same value. Otherwise that
+// is the value, unless current state holds a reference to environment
+// variable or file with the same value.
+func (c *Config) stateSecret(current, value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+	if !c.HashSecrets {
+		if isSecretReference(current) {
+			if resolved, err := resolveSecret(current); err == nil && resolved == value {
+				return current, nil
+			}
+		}
+		return value, nil
+	}
+	if isHashedSecret(current) && secretMatchesHash(value, current) {
+		return current, nil
+	}
+	return hashSecret(value)
+}
+
+// stateConfigSecret returns configured value of a secret to be stored in
+// state. With secrets hashing enabled that is salted hash of the value
+// resolved from environment variable or file, unless it is a hash already.
+func (c *Config) stateConfigSecret(value string) (string, error) {
+	if !c.HashSecrets || value == "" || isHashedSecret(value) {
+		return value, nil
+	}
+	secret, err := resolveSecret(value)
+	if err != nil {
+		return "", err
+	}
+	return hashSecret(secret)
+}
+
+// setSecretsState replaces configured values of given secret attributes in
+// state with their hashes, when secrets hashing is enabled
+func setSecretsState(d *schema.ResourceData, meta interface{}, keys ...string) error {
+	for _, key := range keys {
+		value, err := meta.(*Config).stateConfigSecret(d.Get(key).(string))
+		if err != nil {
+			return fmt.Errorf("error reading %s: %s", key, err)
+		}
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("error reading %s: %s", key, err)
+		}
+	}
+	return nil
+}
+
+// getSecret returns value of a given secret attribute to be sent to API,
+// read from environment variable or file if needed. When state holds hash
+// of unchanged secret, the value is taken from configuration.
+func getSecret(d *schema.ResourceData, key string) (string, error) {
+	value := d.Get(key).(string)
+	if isHashedSecret(value) {
+		config := d.GetRawConfig()
+		if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(key) {
+			return "", fmt.Errorf("value of secret %s is not available in configuration", key)
+		}
+		attr := config.GetAttr(key)
+		if attr.IsNull() || !attr.IsKnown() || !attr.Type().Equals(cty.String) {
+			return "", fmt.Errorf("value of secret %s is not available in configuration", key)
+		}
+		value = attr.AsString()
+	}
+	return resolveSecret(value)
+}
@@ -0,0 +1,110 @@
+package equinix
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretHash(t *testing.T) {
+	// given
+	secret := "licenseToken"
+	// when
+	hash, err := hashSecret(secret)
+	otherHash, _ := hashSecret(secret)
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.True(t, isHashedSecret(hash), "Value is recognized as hash")
+	assert.NotContains(t, hash, secret, "Hash does not contain secret")
+	assert.NotEqual(t, hash, otherHash, "Hashes of the same secret are salted")
+	assert.True(t, secretMatchesHash(secret, hash), "Secret matches its hash")
+	assert.False(t, secretMatchesHash("otherToken", hash), "Other secret does not match hash")
+	assert.False(t, isHashedSecret(secret), "Plain value is not recognized as hash")
+}
+
+func TestResolveSecret(t *testing.T) {
+	// given
+	os.Setenv("TF_EQUINIX_TEST_SECRET", "fromEnv")
+	defer os.Unsetenv("TF_EQUINIX_TEST_SECRET")
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := ioutil.WriteFile(path, []byte("fromFile\n"), 0600); err != nil {
+		t.Fatalf("cannot write secret file: %s", err)
+	}
+	// when
+	plain, plainErr := resolveSecret("plain")
+	env, envErr := resolveSecret("env://TF_EQUINIX_TEST_SECRET")
+	file, fileErr := resolveSecret("file://" + path)
+	_, missingErr := resolveSecret("env://TF_EQUINIX_TEST_MISSING_SECRET")
+	// then
+	assert.Nil(t, plainErr, "Error is not returned for plain value")
+	assert.Equal(t, "plain", plain, "Plain value is returned as it is")
+	assert.Nil(t, envErr, "Error is not returned for environment variable")
+	assert.Equal(t, "fromEnv", env, "Value is read from environment variable")
+	assert.Nil(t, fileErr, "Error is not returned for file")
+	assert.Equal(t, "fromFile", file, "Value is read from file without trailing newline")
+	assert.NotNil(t, missingErr, "Error is returned for missing environment variable")
+}
+
+func TestSuppressHashedSecretDiff(t *testing.T) {
+	// given
+	hash, _ := hashSecret("authKey")
+	os.Setenv("TF_EQUINIX_TEST_SECRET", "authKey")
+	defer os.Unsetenv("TF_EQUINIX_TEST_SECRET")
+	// when
+	same := suppressHashedSecretDiff("authentication_key", hash, "authKey", nil)
+	sameFromEnv := suppressHashedSecretDiff("authentication_key", hash, "env://TF_EQUINIX_TEST_SECRET", nil)
+	changed := suppressHashedSecretDiff("authentication_key", hash, "newAuthKey", nil)
+	plain := suppressHashedSecretDiff("authentication_key", "authKey", "newAuthKey", nil)
+	// then
+	assert.True(t, same, "Diff is suppressed when configured value matches hash")
+	assert.True(t, sameFromEnv, "Diff is suppressed when referenced value matches hash")
+	assert.False(t, changed, "Diff is not suppressed when configured value changes")
+	assert.False(t, plain, "Diff of plain values is not suppressed")
+}
+
+func TestConfig_stateSecret(t *testing.T) {
+	// given
+	disabled := &Config{}
+	enabled := &Config{HashSecrets: true}
+	// when
+	plain, _ := disabled.stateSecret("", "rootPassword")
+	hash, err := enabled.stateSecret("", "rootPassword")
+	kept, _ := enabled.stateSecret(hash, "rootPassword")
+	changed, _ := enabled.stateSecret(hash, "newRootPassword")
+	configHash, _ := enabled.stateConfigSecret(hash)
+	// then
+	assert.Equal(t, "rootPassword", plain, "Value is stored when hashing is disabled")
+	assert.Nil(t, err, "Error is not returned")
+	assert.True(t, secretMatchesHash("rootPassword", hash), "Hash is stored when hashing is enabled")
+	assert.Equal(t, hash, kept, "Hash of unchanged value is kept")
+	assert.NotEqual(t, hash, changed, "Hash of changed value is replaced")
+	assert.Equal(t, hash, configHash, "Hash is not hashed again")
+}
+
+func TestConfig_stateSecretReference(t *testing.T) {
+	// given
+	os.Setenv("TF_EQUINIX_TEST_SECRET", "authKey")
+	defer os.Unsetenv("TF_EQUINIX_TEST_SECRET")
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := ioutil.WriteFile(path, []byte("authKey\n"), 0600); err != nil {
+		t.Fatalf("cannot write secret file: %s", err)
+	}
+	disabled := &Config{}
+	enabled := &Config{HashSecrets: true}
+	// when
+	env, envErr := disabled.stateSecret("env://TF_EQUINIX_TEST_SECRET", "authKey")
+	file, _ := disabled.stateSecret("file://"+path, "authKey")
+	changed, _ := disabled.stateSecret("env://TF_EQUINIX_TEST_SECRET", "newAuthKey")
+	missing, _ := disabled.stateSecret("env://TF_EQUINIX_TEST_MISSING_SECRET", "authKey")
+	hash, _ := enabled.stateSecret("env://TF_EQUINIX_TEST_SECRET", "authKey")
+	// then
+	assert.Nil(t, envErr, "Error is not returned")
+	assert.Equal(t, "env://TF_EQUINIX_TEST_SECRET", env, "Environment variable reference is kept")
+	assert.Equal(t, "file://"+path, file, "File reference is kept")
+	assert.Equal(t, "newAuthKey", changed, "Value changed outside of Terraform replaces reference")
+	assert.Equal(t, "authKey", missing, "Value replaces reference that cannot be resolved")
+	assert.True(t, secretMatchesHash("authKey", hash), "Hash is stored when hashing is enabled")
+}
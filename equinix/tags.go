@@ -0,0 +1,88 @@
+package equinix
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// metalTagsAllSchema returns schema of computed attribute that holds all tags
+// of a resource, including provider default tags
+func metalTagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "All tags attached to the resource, including tags inherited from the provider default_tags configuration",
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// expandMetalTags returns given resource tags merged with provider
+// default tags. Resource tags go first, duplicates are omitted.
+func expandMetalTags(meta interface{}, tags []string) []string {
+	merged := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !isStringInSlice(tag, merged) {
+			merged = append(merged, tag)
+		}
+	}
+	if config, ok := meta.(*Config); ok {
+		for _, tag := range config.DefaultTags {
+			if !isStringInSlice(tag, merged) {
+				merged = append(merged, tag)
+			}
+		}
+	}
+	return merged
+}
+
+// flattenMetalTags returns resource level tags out of all tags returned by
+// the API. Provider default tags are omitted, unless they are explicitly
+// configured on a resource, so they do not cause diffs on resource tags.
+func flattenMetalTags(meta interface{}, configured []string, all []string) []string {
+	config, ok := meta.(*Config)
+	if !ok || len(config.DefaultTags) == 0 {
+		return all
+	}
+	tags := make([]string, 0, len(all))
+	for _, tag := range all {
+		if isStringInSlice(tag, config.DefaultTags) && !isStringInSlice(tag, configured) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// getMetalTags returns resource tags given either as list or set
+func getMetalTags(d resourceDataProvider, key string) []string {
+	switch tags := d.Get(key).(type) {
+	case []interface{}:
+		return convertStringArr(tags)
+	case *schema.Set:
+		return convertStringArr(tags.List())
+	}
+	return nil
+}
+
+// setMetalTags sets both resource level tags and all tags attribute
+// out of tags returned by the API
+func setMetalTags(d *schema.ResourceData, meta interface{}, all []string) error {
+	if err := d.Set("tags", flattenMetalTags(meta, getMetalTags(d, "tags"), all)); err != nil {
+		return err
+	}
+	return d.Set("tags_all", all)
+}
+
+// customizeDiffMetalTagsAll plans update of all tags attribute when either
+// resource tags or provider default tags change
+func customizeDiffMetalTagsAll(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+	expected := expandMetalTags(meta, getMetalTags(d, "tags"))
+	if d.Id() != "" && slicesMatch(expected, getMetalTags(d, "tags_all")) {
+		return nil
+	}
+	return d.SetNew("tags_all", expected)
+}
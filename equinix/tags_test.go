@@ -0,0 +1,29 @@
+package equinix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetalTags_expand(t *testing.T) {
+	// given
+	meta := &Config{DefaultTags: []string{"default", "shared"}}
+	tags := []string{"resource", "shared", "resource"}
+	// when
+	merged := expandMetalTags(meta, tags)
+	// then
+	assert.Equal(t, []string{"resource", "shared", "default"}, merged, "Resource tags go first without duplicates")
+}
+
+func TestMetalTags_flatten(t *testing.T) {
+	// given
+	meta := &Config{DefaultTags: []string{"default", "shared"}}
+	configured := []string{"resource", "shared"}
+	all := []string{"resource", "shared", "default"}
+	// when
+	tags := flattenMetalTags(meta, configured, all)
+	// then
+	assert.Equal(t, []string{"resource", "shared"}, tags, "Not configured default tags are omitted")
+	assert.Equal(t, all, flattenMetalTags(&Config{}, configured, all), "All tags are returned without defaults")
+}
@@ -0,0 +1,187 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/equinix/terraform-provider-equinix/equinix/internal/telemetry"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	telemetryRetriesCounter   = "equinix.http.retries"
+	telemetryThrottledCounter = "equinix.http.throttled"
+
+	throttleReasonAPI           = "api"
+	throttleReasonRateLimit     = "rate_limit"
+	throttleReasonMutationLimit = "mutation_limit"
+)
+
+// ShutdownTelemetry exports remaining telemetry spans and metrics. It should
+// be called before the provider process exits.
+func ShutdownTelemetry() {
+	telemetry.Shutdown()
+}
+
+// traceResources wraps create, read, update and delete functions of given
+// resources or data sources with telemetry spans. Functions have to be
+// context aware, see withOperationContext, so the spans are passed to API
+// requests of the operation. Resources are not changed when telemetry is
+// disabled.
+func traceResources(resources map[string]*schema.Resource, dataSource bool) {
+	if !telemetry.Enabled() {
+		return
+	}
+	kind := "resource"
+	if dataSource {
+		kind = "data_source"
+	}
+	for name, r := range resources {
+		if r.CreateContext != nil {
+			r.CreateContext = traceContextCRUD(kind, name, "create", r.CreateContext)
+		}
+		if r.ReadContext != nil {
+			r.ReadContext = traceContextCRUD(kind, name, "read", r.ReadContext)
+		}
+		if r.UpdateContext != nil {
+			r.UpdateContext = traceContextCRUD(kind, name, "update", r.UpdateContext)
+		}
+		if r.DeleteContext != nil {
+			r.DeleteContext = traceContextCRUD(kind, name, "delete", r.DeleteContext)
+		}
+	}
+}
+
+func traceContextCRUD(kind, name, operation string, f contextCRUDFunc) contextCRUDFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx, span := startCRUDSpan(ctx, kind, name, operation, d)
+		diags := f(ctx, d, meta)
+		endCRUDSpan(span, d, diagnosticsError(diags))
+		return diags
+	}
+}
+
+func startCRUDSpan(ctx context.Context, kind, name, operation string, d *schema.ResourceData) (context.Context, *telemetry.Span) {
+	return telemetry.Start(ctx, fmt.Sprintf("%s %s", operation, name), telemetry.Attributes{
+		"terraform.kind":      kind,
+		"terraform.type":      name,
+		"terraform.operation": operation,
+		"terraform.id":        d.Id(),
+	})
+}
+
+func endCRUDSpan(span *telemetry.Span, d *schema.ResourceData, err error) {
+	// ID is known only after resource is created
+	span.SetAttributes(telemetry.Attributes{"terraform.id": d.Id()})
+	span.End(err)
+}
+
+// diagnosticsError returns summaries of error diagnostics as error or nil
+// when there are no errors
+func diagnosticsError(diags diag.Diagnostics) error {
+	var summaries []string
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			summaries = append(summaries, d.Summary)
+		}
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(summaries, "; "))
+}
+
+// traceStateChangeConf wraps refresh function of given waiter, so every
+// status poll is recorded as telemetry span. Polls are children of a span
+// carried by given context, if any.
+func traceStateChangeConf(ctx context.Context, waiter string, conf *resource.StateChangeConf) *resource.StateChangeConf {
+	if !telemetry.Enabled() {
+		return conf
+	}
+	refresh := conf.Refresh
+	conf.Refresh = func() (interface{}, string, error) {
+		_, span := telemetry.Start(ctx, "poll "+waiter, telemetry.Attributes{
+			"equinix.waiter":         waiter,
+			"equinix.waiter.pending": strings.Join(conf.Pending, ","),
+			"equinix.waiter.target":  strings.Join(conf.Target, ","),
+		})
+		result, state, err := refresh()
+		span.SetAttributes(telemetry.Attributes{"equinix.waiter.state": state})
+		span.End(err)
+		return result, state, err
+	}
+	return conf
+}
+
+// telemetryTransport records a span of every request sent, including
+// retries, and counts requests throttled by API
+type telemetryTransport struct {
+	service string
+	next    http.RoundTripper
+}
+
+// newTelemetryTransport wraps given transport with telemetry or returns the
+// transport as it is when telemetry is disabled
+func newTelemetryTransport(service string, next http.RoundTripper) http.RoundTripper {
+	if !telemetry.Enabled() {
+		return next
+	}
+	return &telemetryTransport{
+		service: service,
+		next:    next,
+	}
+}
+
+func (t *telemetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, span := telemetry.StartClient(req.Context(), "HTTP "+req.Method, telemetry.Attributes{
+		"equinix.service": t.service,
+		"http.method":     req.Method,
+		"http.url":        req.URL.String(),
+	})
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.End(err)
+		return resp, err
+	}
+	span.SetAttributes(telemetry.Attributes{
+		"http.status_code":   resp.StatusCode,
+		"equinix.request_id": resp.Header.Get(requestIDHeader),
+	})
+	if resp.StatusCode == http.StatusTooManyRequests {
+		countThrottled(req.Context(), t.service, throttleReasonAPI)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.End(errors.New(resp.Status))
+	} else {
+		span.End(nil)
+	}
+	return resp, nil
+}
+
+// countRetries returns retryablehttp.RequestLogHook that counts retried
+// requests of a given service
+func countRetries(service string) retryablehttp.RequestLogHook {
+	return func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+		if attempt == 0 {
+			return
+		}
+		telemetry.Count(req.Context(), telemetryRetriesCounter, telemetry.Attributes{
+			"equinix.service": service,
+			"http.method":     req.Method,
+		})
+	}
+}
+
+// countThrottled counts requests delayed or rejected due to rate limits
+func countThrottled(ctx context.Context, service, reason string) {
+	telemetry.Count(ctx, telemetryThrottledCounter, telemetry.Attributes{
+		"equinix.service": service,
+		"equinix.reason":  reason,
+	})
+}
@@ -0,0 +1,163 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	serviceMetal       = "metal"
+	serviceFabric      = "fabric"
+	serviceNetworkEdge = "network_edge"
+)
+
+var services = []string{serviceMetal, serviceFabric, serviceNetworkEdge}
+
+var serviceTitles = map[string]string{
+	serviceMetal:       "Equinix Metal",
+	serviceFabric:      "Equinix Fabric",
+	serviceNetworkEdge: "Equinix Network Edge",
+}
+
+// RateLimit describes client side limits of API requests sent to a single
+// service
+type RateLimit struct {
+	// Maximum number of requests sent per second, zero means no limit
+	RequestsPerSecond float64
+	// Maximum number of concurrent POST, PUT, PATCH and DELETE requests,
+	// zero means no limit
+	MaxConcurrentMutations int
+}
+
+// rateLimiter spaces requests evenly so no more than given number of
+// requests is sent per second
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+	}
+}
+
+// Wait blocks until request can be sent or context is done. Slot of
+// a request canceled while waiting is released.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if err := sleepContext(ctx, l.reserve()); err != nil {
+		l.release()
+		return err
+	}
+	return nil
+}
+
+// reserve reserves next request slot and returns how long to wait for it
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
+
+// release returns reserved request slot that was not used. Requests that
+// reserved later slots are already waiting for them, so the released slot
+// is taken by the next reserving request.
+func (l *rateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next = l.next.Add(-l.interval)
+	if now := time.Now(); l.next.Before(now) {
+		l.next = now
+	}
+}
+
+// sleepContext blocks for given duration or until context is done
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttlingTransport enforces service rate limit on every request, including
+// status polling of resource waiters, and caps number of concurrent mutations
+type throttlingTransport struct {
+	service   string
+	limiter   *rateLimiter
+	mutations chan struct{}
+	next      http.RoundTripper
+}
+
+// newThrottlingTransport wraps given transport with service limits or returns
+// the transport as it is when service is not limited
+func newThrottlingTransport(service string, limit RateLimit, next http.RoundTripper) http.RoundTripper {
+	if limit.RequestsPerSecond <= 0 && limit.MaxConcurrentMutations <= 0 {
+		return next
+	}
+	t := &throttlingTransport{
+		service: service,
+		next:    next,
+	}
+	if limit.RequestsPerSecond > 0 {
+		t.limiter = newRateLimiter(limit.RequestsPerSecond)
+	}
+	if limit.MaxConcurrentMutations > 0 {
+		t.mutations = make(chan struct{}, limit.MaxConcurrentMutations)
+	}
+	return t
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if t.mutations != nil && !isReadOnlyMethod(req.Method) {
+		select {
+		case t.mutations <- struct{}{}:
+			defer func() { <-t.mutations }()
+		default:
+			log.Printf("[DEBUG] Concurrent %s mutations limit reached, waiting to send %s %s", t.service, req.Method, req.URL)
+			countThrottled(ctx, t.service, throttleReasonMutationLimit)
+			select {
+			case t.mutations <- struct{}{}:
+				defer func() { <-t.mutations }()
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	if t.limiter != nil {
+		wait := t.limiter.reserve()
+		if wait > 0 {
+			countThrottled(ctx, t.service, throttleReasonRateLimit)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			t.limiter.release()
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestThrottle_rateLimiter(t *testing.T) {
+	// given
+	limiter := newRateLimiter(20)
+	start := time.Now()
+	// when
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, limiter.Wait(context.Background()))
+	}
+	// then
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond), "Requests are spaced according to the limit")
+}
+
+func TestThrottle_rateLimiterCanceled(t *testing.T) {
+	// given
+	limiter := newRateLimiter(0.1)
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.Nil(t, limiter.Wait(ctx), "First request is not delayed")
+	// when
+	cancel()
+	err := limiter.Wait(ctx)
+	// then
+	assert.Equal(t, context.Canceled, err, "Waiting is canceled with context")
+}
+
+func TestThrottle_rateLimiterCanceledReleased(t *testing.T) {
+	// given
+	limiter := newRateLimiter(10)
+	assert.Nil(t, limiter.Wait(context.Background()), "First request is not delayed")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// when
+	err := limiter.Wait(ctx)
+	start := time.Now()
+	_ = limiter.Wait(context.Background())
+	// then
+	assert.Equal(t, context.DeadlineExceeded, err, "Waiting is canceled with context")
+	assert.Less(t, int64(time.Since(start)), int64(150*time.Millisecond), "Slot of canceled request is taken by the next request")
+}
+
+func TestThrottle_maxConcurrentMutations(t *testing.T) {
+	// given
+	var current, max int32
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	transport := newThrottlingTransport(serviceMetal, RateLimit{MaxConcurrentMutations: 2}, next)
+	// when
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodPost, "https://api.equinix.com/metal/v1/projects", nil)
+			_, _ = transport.RoundTrip(req)
+		}()
+	}
+	wg.Wait()
+	// then
+	assert.Equal(t, int32(2), max, "Number of concurrent mutations is capped")
+}
+
+func TestThrottle_disabled(t *testing.T) {
+	// given
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+	// when
+	transport := newThrottlingTransport(serviceFabric, RateLimit{}, next)
+	// then
+	_, ok := transport.(*throttlingTransport)
+	assert.False(t, ok, "Transport is not wrapped when there are no limits")
+}
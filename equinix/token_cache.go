@@ -0,0 +1,227 @@
+package equinix
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	xoauth2 "golang.org/x/oauth2"
+)
+
+const (
+	tokenCacheFileEnvVar = "EQUINIX_TOKEN_CACHE_FILE"
+	// tokenCacheExpiryDelta is how long before expiry cached tokens are
+	// refreshed, so they do not expire while in use
+	tokenCacheExpiryDelta = 2 * time.Minute
+	// tokenCacheLockTimeout is how long a lock of token cache file is
+	// waited for. Locks older than that are considered abandoned by
+	// terminated processes and are removed.
+	tokenCacheLockTimeout = time.Minute
+	// tokenCacheLockRetryInterval is how often a locked token cache file
+	// is checked
+	tokenCacheLockRetryInterval = 50 * time.Millisecond
+)
+
+// tokenCacheMu serializes access to token cache files of all token
+// sources within the provider process, lock files serialize access of
+// concurrent provider processes
+var tokenCacheMu sync.Mutex
+
+// tokenSourceFunc is an adapter to use a function as xoauth2.TokenSource
+type tokenSourceFunc func() (*xoauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*xoauth2.Token, error) {
+	return f()
+}
+
+// cachedToken is an access token persisted in token cache file
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// cachedTokenSource is a xoauth2.TokenSource that persists tokens in a file,
+// so they can be reused by subsequent provider invocations until shortly
+// before they expire. Tokens are keyed by client ID and token endpoint.
+type cachedTokenSource struct {
+	path string
+	key  string
+	next xoauth2.TokenSource
+
+	mu    sync.Mutex
+	token *xoauth2.Token
+}
+
+// newCachedTokenSource wraps given token source with on-disk token cache.
+// Given source has to fetch a new token on every call, otherwise tokens
+// are not refreshed before they expire.
+func newCachedTokenSource(path, clientID, endpoint string, next xoauth2.TokenSource) xoauth2.TokenSource {
+	return &cachedTokenSource{
+		path: path,
+		key:  fmt.Sprintf("%s@%s", clientID, endpoint),
+		next: next,
+	}
+}
+
+func (s *cachedTokenSource) Token() (*xoauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && !isTokenCacheExpired(s.token.Expiry) {
+		return s.token, nil
+	}
+	token, err := s.cachedToken()
+	if err != nil {
+		return nil, err
+	}
+	// tokens without expiry are used once, as they could not be refreshed
+	if !token.Expiry.IsZero() {
+		s.token = token
+	}
+	return token, nil
+}
+
+// cachedToken returns token from cache file, or fetches a new one and saves
+// it in the file when cached token is missing or close to expiry
+func (s *cachedTokenSource) cachedToken() (*xoauth2.Token, error) {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+	unlock, err := lockTokenCache(s.path)
+	if err != nil {
+		log.Printf("[WARN] Ignoring OAuth token cache: %s", err)
+		return s.next.Token()
+	}
+	defer unlock()
+	tokens, err := readTokenCache(s.path)
+	if err != nil {
+		log.Printf("[WARN] Ignoring OAuth token cache: %s", err)
+	}
+	if cached, ok := tokens[s.key]; ok && !isTokenCacheExpired(cached.Expiry) {
+		log.Printf("[DEBUG] Using cached OAuth token valid until %s", cached.Expiry)
+		return &xoauth2.Token{
+			AccessToken: cached.AccessToken,
+			TokenType:   cached.TokenType,
+			Expiry:      cached.Expiry,
+		}, nil
+	}
+	token, err := s.next.Token()
+	if err != nil {
+		return nil, err
+	}
+	// tokens without expiry are not persisted, as they could not be refreshed
+	if token.Expiry.IsZero() {
+		return token, nil
+	}
+	if tokens == nil {
+		tokens = make(map[string]cachedToken)
+	}
+	tokens[s.key] = cachedToken{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      token.Expiry,
+	}
+	if err := writeTokenCache(s.path, tokens); err != nil {
+		log.Printf("[WARN] Failed to save OAuth token cache: %s", err)
+	}
+	return token, nil
+}
+
+// isTokenCacheExpired returns true when token with given expiry has to be
+// refreshed
+func isTokenCacheExpired(expiry time.Time) bool {
+	return !time.Now().Add(tokenCacheExpiryDelta).Before(expiry)
+}
+
+// lockTokenCache creates lock file next to token cache file, waiting while
+// it is held by another process. Returned function removes the lock file.
+func lockTokenCache(path string) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(tokenCacheLockTimeout)
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			lock.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > tokenCacheLockTimeout {
+			log.Printf("[DEBUG] Removing abandoned OAuth token cache lock %s", lockPath)
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock file %s", lockPath)
+		}
+		time.Sleep(tokenCacheLockRetryInterval)
+	}
+}
+
+// readTokenCache reads tokens from cache file. Files readable by other
+// users are ignored.
+func readTokenCache(path string) (map[string]cachedToken, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("file %s is accessible by other users, permissions should be 0600", path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make(map[string]cachedToken)
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("cannot parse file %s: %s", path, err)
+	}
+	return tokens, nil
+}
+
+// writeTokenCache atomically replaces cache file with given tokens,
+// expired tokens are dropped
+func writeTokenCache(path string, tokens map[string]cachedToken) error {
+	now := time.Now()
+	for key, token := range tokens {
+		if token.Expiry.Before(now) {
+			delete(tokens, key)
+		}
+	}
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
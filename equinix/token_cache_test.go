@@ -0,0 +1,171 @@
+package equinix
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	xoauth2 "golang.org/x/oauth2"
+)
+
+type countingTokenSource struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (s *countingTokenSource) Token() (*xoauth2.Token, error) {
+	s.calls++
+	return &xoauth2.Token{
+		AccessToken: "token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(s.expiry),
+	}, nil
+}
+
+func TestCachedTokenSource_reuse(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	next := &countingTokenSource{expiry: time.Hour}
+	first := newCachedTokenSource(path, "clientID", "https://api.equinix.com", next)
+	second := newCachedTokenSource(path, "clientID", "https://api.equinix.com", next)
+	other := newCachedTokenSource(path, "clientID", "https://uatapi.equinix.com", next)
+	// when
+	_, err := first.Token()
+	token, _ := second.Token()
+	_, _ = other.Token()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, "token", token.AccessToken, "Cached token is returned")
+	assert.Equal(t, 2, next.calls, "Token is fetched once per endpoint")
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "Cache file is readable only by owner")
+}
+
+func TestCachedTokenSource_refresh(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	next := &countingTokenSource{expiry: time.Minute}
+	source := newCachedTokenSource(path, "clientID", "https://api.equinix.com", next)
+	// when
+	_, _ = source.Token()
+	_, _ = source.Token()
+	// then
+	assert.Equal(t, 2, next.calls, "Token close to expiry is refreshed")
+}
+
+func TestCachedTokenSource_insecureFile(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("cannot write token cache file: %s", err)
+	}
+	// when
+	_, err := readTokenCache(path)
+	// then
+	assert.NotNil(t, err, "Error is returned for file readable by others")
+}
+
+func TestConfig_clientCredentialsTokenSourceRefresh(t *testing.T) {
+	// given
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token%d","token_timeout":"60"}`, n)
+	}))
+	defer server.Close()
+	config := &Config{
+		BaseURL:        server.URL,
+		ClientID:       "clientID",
+		ClientSecret:   "clientSecret",
+		TokenCacheFile: filepath.Join(t.TempDir(), "tokens.json"),
+	}
+	source := config.newClientCredentialsTokenSource(serviceFabric, http.DefaultTransport)
+	// when
+	first, err := source.Token()
+	second, _ := source.Token()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, "token1", first.AccessToken, "Token is fetched")
+	assert.Equal(t, "token2", second.AccessToken, "Token close to expiry is fetched again")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "Token endpoint is called for every refresh")
+}
+
+func TestConfig_clientCredentialsTokenSourceCache(t *testing.T) {
+	// given
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_timeout":"3600"}`)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	newConfig := func() *Config {
+		return &Config{
+			BaseURL:        server.URL,
+			ClientID:       "clientID",
+			ClientSecret:   "clientSecret",
+			TokenCacheFile: path,
+		}
+	}
+	// when
+	first := newConfig().newClientCredentialsTokenSource(serviceFabric, http.DefaultTransport)
+	second := newConfig().newClientCredentialsTokenSource(serviceFabric, http.DefaultTransport)
+	_, _ = first.Token()
+	_, _ = first.Token()
+	token, err := second.Token()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, "token", token.AccessToken, "Cached token is returned")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Token is fetched once")
+}
+
+func TestLockTokenCache_wait(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	unlock, err := lockTokenCache(path)
+	if err != nil {
+		t.Fatalf("cannot lock token cache: %s", err)
+	}
+	released := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		released <- time.Now()
+		unlock()
+	}()
+	// when
+	secondUnlock, err := lockTokenCache(path)
+	locked := time.Now()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.False(t, locked.Before(<-released), "Lock is acquired after it is released")
+	secondUnlock()
+	_, err = os.Stat(path + ".lock")
+	assert.True(t, os.IsNotExist(err), "Lock file is removed")
+}
+
+func TestLockTokenCache_abandoned(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path+".lock", nil, 0o600); err != nil {
+		t.Fatalf("cannot write lock file: %s", err)
+	}
+	abandoned := time.Now().Add(-2 * tokenCacheLockTimeout)
+	if err := os.Chtimes(path+".lock", abandoned, abandoned); err != nil {
+		t.Fatalf("cannot change lock file times: %s", err)
+	}
+	// when
+	unlock, err := lockTokenCache(path)
+	// then
+	assert.Nil(t, err, "Abandoned lock is removed")
+	if unlock != nil {
+		unlock()
+	}
+}
@@ -0,0 +1,107 @@
+package equinix
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// httpTransport returns base transport of all API clients, including OAuth
+// token requests, configured with custom CA bundle, client certificate and
+// proxy settings. Transport is created once and shared by all clients.
+func (c *Config) httpTransport() (http.RoundTripper, error) {
+	if c.transport != nil {
+		return c.transport, nil
+	}
+	if c.CACertFile == "" && c.ClientCertFile == "" && c.ProxyURL == "" && !c.InsecureSkipVerify {
+		c.transport = http.DefaultTransport
+		return c.transport, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %s", c.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	c.transport = transport
+	return c.transport, nil
+}
+
+// baseTransport returns base transport of API clients. Settings are validated
+// by Load, but clients of a configuration that was not loaded get a transport
+// failing all requests with the error of invalid settings, so requests are
+// never sent without configured CA bundle, client certificate or proxy.
+func (c *Config) baseTransport() http.RoundTripper {
+	transport, err := c.httpTransport()
+	if err != nil {
+		return failingTransport{err: err}
+	}
+	return transport
+}
+
+// contextTransport sends requests with a given context. Equinix Metal client
+// creates requests without context, thus they are bound to the context of
+// resource operation by its transport.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// failingTransport fails all requests with a given error
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("invalid transport settings: %s", t.err)
+}
+
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CACertFile != "" {
+		pem, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA certificate file: %s", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA certificate file %s", c.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both client certificate and client key files have to be set")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
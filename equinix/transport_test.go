@@ -0,0 +1,100 @@
+package equinix
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_httpTransportDefault(t *testing.T) {
+	// given
+	config := &Config{}
+	// when
+	transport, err := config.httpTransport()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, http.DefaultTransport, transport, "Default transport is used")
+}
+
+func TestConfig_httpTransportCACert(t *testing.T) {
+	// given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caCertFile, caCert, 0o600); err != nil {
+		t.Fatalf("cannot write CA certificate file: %s", err)
+	}
+	config := &Config{CACertFile: caCertFile}
+	// when
+	transport, err := config.httpTransport()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	assert.Nil(t, err, "Server certificate is trusted")
+	if resp != nil {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+}
+
+func TestConfig_httpTransportProxy(t *testing.T) {
+	// given
+	config := &Config{ProxyURL: "http://proxy.example.com:3128", InsecureSkipVerify: true}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.equinix.com", nil)
+	// when
+	transport, err := config.httpTransport()
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	httpTransport := transport.(*http.Transport)
+	proxyURL, _ := httpTransport.Proxy(req)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String(), "Proxy is used")
+	assert.True(t, httpTransport.TLSClientConfig.InsecureSkipVerify, "Verification is skipped")
+}
+
+func TestConfig_httpTransportInvalidClientCert(t *testing.T) {
+	// given
+	config := &Config{ClientCertFile: filepath.Join(t.TempDir(), "missing.pem")}
+	// when
+	_, err := config.httpTransport()
+	// then
+	assert.NotNil(t, err, "Error is returned when client key is missing")
+}
+
+func TestConfig_loadInvalidTransport(t *testing.T) {
+	// given
+	configs := []*Config{
+		{CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{ClientCertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{ProxyURL: "http://proxy example.com"},
+	}
+	for _, config := range configs {
+		config.BaseURL = "https://api.equinix.com"
+		config.AuthToken = "someToken"
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.NotNil(t, err, "Error is returned for invalid transport settings")
+	}
+}
+
+func TestConfig_baseTransportInvalid(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	config := &Config{CACertFile: filepath.Join(t.TempDir(), "missing.pem")}
+	// when
+	_, err := (&http.Client{Transport: config.baseTransport()}).Get(server.URL)
+	// then
+	assert.NotNil(t, err, "Request is not sent without configured CA certificate")
+}
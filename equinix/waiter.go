@@ -0,0 +1,57 @@
+package equinix
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	// defaultPollInterval is the initial interval between status checks of
+	// waiters without resource specific minimum
+	defaultPollInterval = time.Second
+	// defaultPollMaxInterval is the longest interval between status checks,
+	// the same as of resource.StateChangeConf backoff
+	defaultPollMaxInterval = 10 * time.Second
+)
+
+// stateWaiter applies provider polling settings and telemetry to a given
+// waiter. Status is checked with exponential backoff, starting at poll
+// interval, or resource specific minimum when poll interval is not set,
+// and doubling after every check up to poll max interval.
+func (c *Config) stateWaiter(ctx context.Context, name string, conf *resource.StateChangeConf) *resource.StateChangeConf {
+	var interval, maxInterval time.Duration
+	if c != nil {
+		interval, maxInterval = c.PollInterval, c.PollMaxInterval
+	}
+	applyPollBackoff(conf, interval, maxInterval)
+	return traceStateChangeConf(ctx, name, conf)
+}
+
+// applyPollBackoff wraps refresh function of a given waiter, so it sets
+// PollInterval used by resource.StateChangeConf before the next check
+func applyPollBackoff(conf *resource.StateChangeConf, interval, maxInterval time.Duration) {
+	if maxInterval <= 0 {
+		maxInterval = defaultPollMaxInterval
+	}
+	if interval <= 0 {
+		interval = conf.MinTimeout
+	}
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+	refresh := conf.Refresh
+	next := interval
+	conf.PollInterval = next
+	conf.Refresh = func() (interface{}, string, error) {
+		conf.PollInterval = next
+		if next *= 2; next > maxInterval {
+			next = maxInterval
+		}
+		return refresh()
+	}
+}
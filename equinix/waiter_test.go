@@ -0,0 +1,51 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPollBackoff(t *testing.T) {
+	// given
+	conf := &resource.StateChangeConf{
+		Refresh: func() (interface{}, string, error) {
+			return struct{}{}, "pending", nil
+		},
+		MinTimeout: 3 * time.Second,
+	}
+	var intervals []time.Duration
+	// when
+	applyPollBackoff(conf, 0, 20*time.Second)
+	for i := 0; i < 5; i++ {
+		_, _, _ = conf.Refresh()
+		intervals = append(intervals, conf.PollInterval)
+	}
+	// then
+	assert.Equal(t, []time.Duration{3 * time.Second, 6 * time.Second, 12 * time.Second, 20 * time.Second, 20 * time.Second}, intervals, "Poll interval doubles up to maximum")
+}
+
+func TestConfig_stateWaiter(t *testing.T) {
+	// given
+	config := &Config{PollInterval: 2 * time.Second, PollMaxInterval: 5 * time.Second}
+	checks := 0
+	conf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"done"},
+		Refresh: func() (interface{}, string, error) {
+			checks++
+			return struct{}{}, "done", nil
+		},
+		Timeout:    time.Minute,
+		MinTimeout: 10 * time.Second,
+	}
+	// when
+	_, err := config.stateWaiter(context.Background(), "test", conf).WaitForStateContext(context.Background())
+	// then
+	assert.Nil(t, err, "Error is not returned")
+	assert.Equal(t, 1, checks, "Status is checked once")
+	assert.Equal(t, 2*time.Second, conf.PollInterval, "Provider poll interval overrides resource minimum")
+}
@@ -16,13 +16,18 @@ func main() {
 	flag.Parse()
 	opts := &plugin.ServeOpts{ProviderFunc: equinix.Provider}
 
-	if debugMode {
-		err := plugin.Debug(context.Background(), "registry.terraform.io/equinix/equinix", opts)
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		return
+	err := serve(opts, debugMode)
+	// log.Fatal exits without running deferred functions
+	equinix.ShutdownTelemetry()
+	if err != nil {
+		log.Fatal(err.Error())
 	}
+}
 
+func serve(opts *plugin.ServeOpts, debugMode bool) error {
+	if debugMode {
+		return plugin.Debug(context.Background(), "registry.terraform.io/equinix/equinix", opts)
+	}
 	plugin.Serve(opts)
+	return nil
 }